@@ -0,0 +1,73 @@
+// Command yaml2struct writes Go struct definitions with yaml tags for example yaml documents.
+//
+// Usage:
+//
+//	yaml2struct [-package name] [-type name] [-o file] [file ...]
+//
+// All the documents in the files, or in the standard input if there are none, are examples of the same format.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/xshoji/go-rough-yaml/goroughyaml"
+	"io"
+	"io/ioutil"
+	"os"
+)
+
+func main() {
+	options := goroughyaml.GoStructOptions{}
+	flag.StringVar(&options.Package, "package", "main", "package name of the generated code")
+	flag.StringVar(&options.TypeName, "type", "Config", "type name of the whole document")
+	output := flag.String("o", "", "output file (default standard output)")
+	flag.Parse()
+
+	var docs []*goroughyaml.RoughYaml
+	var err error
+	if flag.NArg() == 0 {
+		docs, err = readDocuments(os.Stdin, docs)
+	}
+	for _, path := range flag.Args() {
+		if err != nil {
+			break
+		}
+		var file *os.File
+		if file, err = os.Open(path); err == nil {
+			docs, err = readDocuments(file, docs)
+			file.Close()
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	source, err := goroughyaml.GenerateGoStructs(options, docs...)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if *output == "" {
+		_, err = os.Stdout.Write(source)
+	} else {
+		err = ioutil.WriteFile(*output, source, 0644)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// readDocuments appends all the yaml documents in r to docs.
+func readDocuments(r io.Reader, docs []*goroughyaml.RoughYaml) ([]*goroughyaml.RoughYaml, error) {
+	decoder := goroughyaml.NewDecoder(r)
+	for {
+		doc := &goroughyaml.RoughYaml{}
+		if err := decoder.Decode(doc); err == io.EOF {
+			return docs, nil
+		} else if err != nil {
+			return docs, err
+		}
+		docs = append(docs, doc)
+	}
+}
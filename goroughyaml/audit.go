@@ -0,0 +1,197 @@
+package goroughyaml
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"strings"
+	"time"
+)
+
+// AuditEntry is a Change recorded in the audit log with the time it was made.
+type AuditEntry struct {
+	Change
+	Time time.Time
+}
+
+// AuditLog is the list of the edits of a document, oldest first.
+type AuditLog []AuditEntry
+
+// auditLog records the edits of a document while it is enabled.
+type auditLog struct {
+	enabled bool
+	entries AuditLog
+}
+
+// EnableAuditLog starts recording every edit of the document of the node in its audit log.
+// Values are copied when they are recorded, so later edits do not change the log.
+// It does nothing for a node which does not belong to a document.
+func (o *RoughYaml) EnableAuditLog() {
+	if o.doc != nil {
+		o.doc.audit.enabled = true
+	}
+}
+
+// DisableAuditLog stops recording edits. The recorded entries are kept.
+func (o *RoughYaml) DisableAuditLog() {
+	if o.doc != nil {
+		o.doc.audit.enabled = false
+	}
+}
+
+// ClearAuditLog removes the recorded entries.
+func (o *RoughYaml) ClearAuditLog() {
+	if o.doc != nil {
+		o.doc.audit.entries = nil
+	}
+}
+
+// AuditLog returns a copy of the entries recorded since EnableAuditLog, or nil for a node which does not belong to a document.
+func (o *RoughYaml) AuditLog() AuditLog {
+	if o.doc == nil {
+		return nil
+	}
+	return append(AuditLog{}, o.doc.audit.entries...)
+}
+
+func (a *auditLog) record(change Change) {
+	if !a.enabled {
+		return
+	}
+	change.Path = append([]string{}, change.Path...)
+	if change.From != nil {
+		change.From = append([]string{}, change.From...)
+	}
+	change.OldValue = cloneValue(change.OldValue)
+	change.NewValue = cloneValue(change.NewValue)
+	a.entries = append(a.entries, AuditEntry{Change: change, Time: time.Now()})
+}
+
+// JSONPatch returns the log as a JSON Patch (RFC 6902) document.
+// A rename is a "move" operation, and a reorder replaces the mapping with its new order
+// because JSON Patch has no operation which only changes the order of keys.
+func (l AuditLog) JSONPatch() ([]byte, error) {
+	buffer := &bytes.Buffer{}
+	buffer.WriteString("[")
+	for index, entry := range l {
+		if index > 0 {
+			buffer.WriteString(",")
+		}
+		buffer.WriteString("\n  {\"op\":")
+		switch entry.Operation {
+		case OperationAdd:
+			buffer.WriteString(`"add"`)
+		case OperationDelete:
+			buffer.WriteString(`"remove"`)
+		case OperationRename:
+			buffer.WriteString(`"move","from":`)
+			writeJSONString(buffer, jsonPointer(entry.From))
+		default:
+			buffer.WriteString(`"replace"`)
+		}
+		buffer.WriteString(`,"path":`)
+		writeJSONString(buffer, jsonPointer(entry.Path))
+		if entry.Operation != OperationDelete && entry.Operation != OperationRename {
+			buffer.WriteString(`,"value":`)
+			if err := writeJSON(buffer, entry.NewValue); err != nil {
+				return nil, fmt.Errorf("value of %v: %v", describePath(entry.Path), err)
+			}
+		}
+		buffer.WriteString("}")
+	}
+	if len(l) > 0 {
+		buffer.WriteString("\n")
+	}
+	buffer.WriteString("]\n")
+	return buffer.Bytes(), nil
+}
+
+// Changelog returns the log as text with one line per edit, such as
+//
+//	2024-01-02T15:04:05Z replace 'database.port': 5432 -> 5433
+//
+// Values are written as JSON, so that the string "5432" and the number 5432 can be told apart.
+func (l AuditLog) Changelog() string {
+	builder := &strings.Builder{}
+	for _, entry := range l {
+		fmt.Fprintf(builder, "%v %v %v", entry.Time.UTC().Format(time.RFC3339), entry.Operation, describePath(entry.Path))
+		switch entry.Operation {
+		case OperationAdd:
+			fmt.Fprintf(builder, ": %v", jsonText(entry.NewValue))
+		case OperationReplace:
+			fmt.Fprintf(builder, ": %v -> %v", jsonText(entry.OldValue), jsonText(entry.NewValue))
+		case OperationDelete:
+			fmt.Fprintf(builder, " (was %v)", jsonText(entry.OldValue))
+		case OperationRename:
+			fmt.Fprintf(builder, " (was %v)", describePath(entry.From))
+		}
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+// jsonPointer returns path as a JSON Pointer (RFC 6901).
+func jsonPointer(path []string) string {
+	builder := &strings.Builder{}
+	for _, key := range path {
+		builder.WriteString("/")
+		builder.WriteString(strings.Replace(strings.Replace(key, "~", "~0", -1), "/", "~1", -1))
+	}
+	return builder.String()
+}
+
+func jsonText(value interface{}) string {
+	buffer := &bytes.Buffer{}
+	if err := writeJSON(buffer, value); err != nil {
+		return fmt.Sprint(value)
+	}
+	return buffer.String()
+}
+
+// writeJSON writes value as compact JSON, keeping the order of the keys of mappings.
+func writeJSON(buffer *bytes.Buffer, value interface{}) error {
+	value, err := normalizeValue(value)
+	if err != nil {
+		return err
+	}
+	switch v := value.(type) {
+	case yaml.MapSlice:
+		buffer.WriteString("{")
+		for index, item := range v {
+			if index > 0 {
+				buffer.WriteString(",")
+			}
+			writeJSONString(buffer, keyString(item.Key))
+			buffer.WriteString(":")
+			if err := writeJSON(buffer, item.Value); err != nil {
+				return err
+			}
+		}
+		buffer.WriteString("}")
+		return nil
+	case []interface{}:
+		buffer.WriteString("[")
+		for index, element := range v {
+			if index > 0 {
+				buffer.WriteString(",")
+			}
+			if err := writeJSON(buffer, element); err != nil {
+				return err
+			}
+		}
+		buffer.WriteString("]")
+		return nil
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	buffer.Write(encoded)
+	return nil
+}
+
+func writeJSONString(buffer *bytes.Buffer, s string) {
+	encoded, _ := json.Marshal(s)
+	buffer.Write(encoded)
+}
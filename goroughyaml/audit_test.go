@@ -0,0 +1,116 @@
+package goroughyaml
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAuditLog(t *testing.T) {
+	//---------------------
+	// init
+	yamlString := `
+database:
+  host: localhost
+  port: 5432
+a/b: 1
+`
+	var expectedValue interface{}
+	var actualValue interface{}
+
+	roughYamlObj := FromYaml(yamlString)
+	roughYamlObj.Set("a/b", 0)
+	roughYamlObj.EnableAuditLog()
+	roughYamlObj.Get("database").Set("port", "5433")
+	roughYamlObj.Get("database").SetForce("options", []interface{}{"ssl"})
+	roughYamlObj.Get("database").Delete("host")
+	roughYamlObj.RenameKey("a/b", "c")
+	roughYamlObj.DisableAuditLog()
+	roughYamlObj.Set("c", 2)
+	auditLog := roughYamlObj.AuditLog()
+	for index := range auditLog {
+		auditLog[index].Time = time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	}
+
+	//
+	//
+	//---------------------
+	// success (only edits while enabled are recorded)
+	expectedValue = 4
+	actualValue = len(auditLog)
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+	}
+	t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+
+	//
+	//
+	//---------------------
+	// success (json patch)
+	expectedValue = `[
+  {"op":"replace","path":"/database/port","value":"5433"},
+  {"op":"add","path":"/database/options","value":["ssl"]},
+  {"op":"remove","path":"/database/host"},
+  {"op":"move","from":"/a~1b","path":"/c"}
+]
+`
+	patch, err := auditLog.JSONPatch()
+	actualValue = string(patch)
+	if err != nil || actualValue != expectedValue {
+		t.Errorf("<< FAILED >>> : %v", err)
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+
+	//
+	//
+	//---------------------
+	// success (changelog)
+	expectedValue = `2024-01-02T15:04:05Z replace 'database.port': 5432 -> "5433"
+2024-01-02T15:04:05Z add 'database.options': ["ssl"]
+2024-01-02T15:04:05Z delete 'database.host' (was "localhost")
+2024-01-02T15:04:05Z rename 'c' (was 'a/b')
+`
+	actualValue = auditLog.Changelog()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+
+	//
+	//
+	//---------------------
+	// success (recorded values are copies)
+	options := roughYamlObj.Get("database").Get("options").Value().([]interface{})
+	options[0] = "tls"
+	expectedValue = "ssl"
+	actualValue = roughYamlObj.AuditLog()[1].NewValue.([]interface{})[0]
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+	}
+	t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+
+	//
+	//
+	//---------------------
+	// success (clear)
+	roughYamlObj.ClearAuditLog()
+	if len(roughYamlObj.AuditLog()) != 0 {
+		t.Errorf("<< FAILED >>>")
+	}
+}
+
+func TestAuditLogDetachedNode(t *testing.T) {
+	//---------------------
+	// init
+	var roughYamlObj RoughYaml
+
+	//
+	//
+	//---------------------
+	// success
+	roughYamlObj.EnableAuditLog()
+	roughYamlObj.DisableAuditLog()
+	roughYamlObj.ClearAuditLog()
+	if log := roughYamlObj.AuditLog(); log != nil {
+		t.Errorf("<< FAILED >>> : %v", log)
+	}
+}
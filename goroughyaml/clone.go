@@ -0,0 +1,187 @@
+package goroughyaml
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"reflect"
+	"strconv"
+)
+
+// ScalarComparison selects how Equal compares scalar values and mapping keys.
+type ScalarComparison int
+
+const (
+	// CompareStrict treats scalars as equal if they have the same type and value, so 1, 1.0 and "1" all differ.
+	CompareStrict ScalarComparison = iota
+	// CompareNumeric treats numbers as equal if they have the same value, so 1 equals 1.0 but not "1".
+	CompareNumeric
+	// CompareLoose treats scalars as equal if they have the same number value or the same string form,
+	// so 1, 1.0, "1" and "1.0" are all equal.
+	CompareLoose
+)
+
+// EqualOptions configures Equal.
+type EqualOptions struct {
+	// IgnoreKeyOrder treats mappings with the same keys in a different order as equal.
+	IgnoreKeyOrder bool
+	// Scalars selects how scalar values and mapping keys are compared.
+	Scalars ScalarComparison
+}
+
+// Clone returns a deep copy of the node as the root of an independent document.
+func (o *RoughYaml) Clone() *RoughYaml {
+	var contents interface{}
+	switch v := o.GetContents().(type) {
+	case *yaml.MapSlice:
+		mapSlice := cloneMapSlice(*v)
+		contents = &mapSlice
+	case *interface{}:
+		value := cloneValue(*v)
+		contents = &value
+	}
+	cloned := newRoughYaml(contents)
+	cloned.err = o.err
+	return &cloned
+}
+
+// cloneValue returns a deep copy of a value in a document.
+func cloneValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case yaml.MapSlice:
+		return cloneMapSlice(v)
+	case *yaml.MapSlice:
+		if v == nil {
+			return v
+		}
+		mapSlice := cloneMapSlice(*v)
+		return &mapSlice
+	case []interface{}:
+		slice := make([]interface{}, len(v))
+		for index, element := range v {
+			slice[index] = cloneValue(element)
+		}
+		return slice
+	case map[interface{}]interface{}:
+		m := make(map[interface{}]interface{}, len(v))
+		for key, element := range v {
+			m[key] = cloneValue(element)
+		}
+		return m
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, element := range v {
+			m[key] = cloneValue(element)
+		}
+		return m
+	}
+	return value
+}
+
+func cloneMapSlice(mapSlice yaml.MapSlice) yaml.MapSlice {
+	cloned := make(yaml.MapSlice, len(mapSlice))
+	for index, item := range mapSlice {
+		cloned[index] = yaml.MapItem{Key: cloneValue(item.Key), Value: cloneValue(item.Value)}
+	}
+	return cloned
+}
+
+// Equal reports whether the node and other hold the same value, compared according to options.
+func (o *RoughYaml) Equal(other *RoughYaml, options EqualOptions) bool {
+	return valuesEqual(o.GetContents(), other.GetContents(), options)
+}
+
+func valuesEqual(a interface{}, b interface{}, options EqualOptions) bool {
+	a, errA := normalizeValue(a)
+	b, errB := normalizeValue(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	switch x := a.(type) {
+	case yaml.MapSlice:
+		y, ok := b.(yaml.MapSlice)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		if options.IgnoreKeyOrder {
+			return mapSlicesEqualUnordered(x, y, options)
+		}
+		for index := range x {
+			if !scalarsEqual(x[index].Key, y[index].Key, options) || !valuesEqual(x[index].Value, y[index].Value, options) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		y, ok := b.([]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for index := range x {
+			if !valuesEqual(x[index], y[index], options) {
+				return false
+			}
+		}
+		return true
+	}
+	switch b.(type) {
+	case yaml.MapSlice, []interface{}:
+		return false
+	}
+	return scalarsEqual(a, b, options)
+}
+
+func mapSlicesEqualUnordered(a yaml.MapSlice, b yaml.MapSlice, options EqualOptions) bool {
+	matched := make([]bool, len(b))
+	for _, itemA := range a {
+		found := false
+		for index, itemB := range b {
+			if !matched[index] && scalarsEqual(itemA.Key, itemB.Key, options) {
+				if !valuesEqual(itemA.Value, itemB.Value, options) {
+					return false
+				}
+				matched[index] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func scalarsEqual(a interface{}, b interface{}, options EqualOptions) bool {
+	if options.Scalars != CompareStrict {
+		numberA, okA := numberValue(a, options.Scalars == CompareLoose)
+		numberB, okB := numberValue(b, options.Scalars == CompareLoose)
+		if okA && okB {
+			return numberA == numberB
+		}
+	}
+	if options.Scalars == CompareLoose && a != nil && b != nil {
+		return fmt.Sprint(a) == fmt.Sprint(b)
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// numberValue returns a number as float64. With fromString, a string holding a number is converted too.
+func numberValue(value interface{}, fromString bool) (float64, bool) {
+	if s, ok := value.(string); ok {
+		if !fromString {
+			return 0, false
+		}
+		number, err := strconv.ParseFloat(s, 64)
+		return number, err == nil
+	}
+	reflected := reflect.ValueOf(value)
+	switch reflected.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(reflected.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(reflected.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return reflected.Float(), true
+	}
+	return 0, false
+}
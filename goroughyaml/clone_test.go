@@ -0,0 +1,116 @@
+package goroughyaml
+
+import (
+	"testing"
+)
+
+func TestClone(t *testing.T) {
+	//---------------------
+	// init
+	yamlString := `
+aaa:
+  bbb:
+    bbb1: bbb
+  ccc:
+  - ccc1
+  - ddd: ddd1
+`
+	var expectedValue interface{}
+	var actualValue interface{}
+
+	roughYamlObj := FromYaml(yamlString)
+
+	//
+	//
+	//---------------------
+	// success (editing the clone keeps the original)
+	cloned := roughYamlObj.Clone()
+	cloned.Get("aaa").Get("bbb").Set("bbb1", "changed")
+	cloned.Get("aaa").Get("ccc").Get("1").Set("ddd", "changed")
+	cloned.Get("aaa").SetForce("eee", "added")
+	expectedValue = `aaa:
+  bbb:
+    bbb1: bbb
+  ccc:
+  - ccc1
+  - ddd: ddd1
+`
+	actualValue, _ = roughYamlObj.ToYaml()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+	expectedValue = "changed"
+	actualValue = cloned.Get("aaa").Get("ccc").Get("1").Get("ddd").Value()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+	}
+	t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+
+	//
+	//
+	//---------------------
+	// success (subtree)
+	expectedValue = "ddd1"
+	actualValue = roughYamlObj.Get("aaa").Get("ccc").Clone().Get("1").Get("ddd").Value()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+	}
+	t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+}
+
+func TestEqual(t *testing.T) {
+	//---------------------
+	// init
+	roughYamlObj1 := FromYaml(`
+aaa:
+  bbb: 1
+  ccc: [x, y]
+`)
+	roughYamlObj2 := FromYaml(`
+aaa:
+  ccc: [x, y]
+  bbb: 1.0
+`)
+	roughYamlObj3 := FromYaml(`
+aaa:
+  bbb: "1"
+  ccc: [x, y]
+`)
+
+	//
+	//
+	//---------------------
+	// success (strict)
+	if !roughYamlObj1.Equal(roughYamlObj1.Clone(), EqualOptions{}) {
+		t.Errorf("<< FAILED >>>")
+	}
+	if roughYamlObj1.Equal(&roughYamlObj3, EqualOptions{}) {
+		t.Errorf("<< FAILED >>>")
+	}
+
+	//
+	//
+	//---------------------
+	// success (key order)
+	if roughYamlObj1.Equal(&roughYamlObj2, EqualOptions{Scalars: CompareNumeric}) {
+		t.Errorf("<< FAILED >>>")
+	}
+	if !roughYamlObj1.Equal(&roughYamlObj2, EqualOptions{Scalars: CompareNumeric, IgnoreKeyOrder: true}) {
+		t.Errorf("<< FAILED >>>")
+	}
+
+	//
+	//
+	//---------------------
+	// success (scalar comparison)
+	if roughYamlObj1.Equal(&roughYamlObj3, EqualOptions{Scalars: CompareNumeric}) {
+		t.Errorf("<< FAILED >>>")
+	}
+	if !roughYamlObj1.Equal(&roughYamlObj3, EqualOptions{Scalars: CompareLoose}) {
+		t.Errorf("<< FAILED >>>")
+	}
+	if !roughYamlObj2.Equal(&roughYamlObj3, EqualOptions{Scalars: CompareLoose, IgnoreKeyOrder: true}) {
+		t.Errorf("<< FAILED >>>")
+	}
+}
@@ -0,0 +1,136 @@
+package goroughyaml
+
+import (
+	"gopkg.in/yaml.v2"
+	"strconv"
+)
+
+// DefaultsPlacement selects where ApplyDefaults inserts missing keys.
+type DefaultsPlacement int
+
+const (
+	// DefaultsAppend adds missing keys after the existing keys of their mapping, in the order of the schema.
+	DefaultsAppend DefaultsPlacement = iota
+	// DefaultsSchemaOrder inserts each missing key before the first existing key which follows it in the schema.
+	DefaultsSchemaOrder
+)
+
+// DefaultsOptions configures ApplyDefaultsWithOptions.
+type DefaultsOptions struct {
+	Placement DefaultsPlacement
+}
+
+// ApplyDefaults adds the keys which are missing in the node and have a default value in schema.
+// A missing key whose schema has no default but has properties with defaults is added as a mapping of them.
+// Defaults are applied to nested mappings and to the mappings in sequences whose items have a schema,
+// following $ref. Existing keys are not changed and keep their order.
+func (o *RoughYaml) ApplyDefaults(schema *Schema) error {
+	return o.ApplyDefaultsWithOptions(schema, DefaultsOptions{})
+}
+
+// ApplyDefaultsWithOptions is ApplyDefaults configured by options.
+func (o *RoughYaml) ApplyDefaultsWithOptions(schema *Schema, options DefaultsOptions) error {
+	if o.err != nil {
+		return o.err
+	}
+	return schema.applyDefaults(o, schema.root, options)
+}
+
+func (s *Schema) applyDefaults(node *RoughYaml, schema interface{}, options DefaultsOptions) error {
+	object := s.schemaObject(schema)
+	if object == nil {
+		return nil
+	}
+	switch contents := plainValue(node.GetContents()).(type) {
+	case []interface{}:
+		items, ok := schemaKeyword(object, "items")
+		if !ok {
+			return nil
+		}
+		for index := range contents {
+			if err := s.applyDefaults(node.Get(strconv.Itoa(index)), items, options); err != nil {
+				return err
+			}
+		}
+		return nil
+	case yaml.MapSlice, nil:
+	default:
+		return nil
+	}
+	properties := plainMapping(lookupKeyword(object, "properties"))
+	for order, property := range properties {
+		name := keyString(property.Key)
+		if child := node.Get(name); child.err == nil {
+			if err := s.applyDefaults(child, property.Value, options); err != nil {
+				return err
+			}
+			continue
+		}
+		value, ok := s.defaultValue(property.Value)
+		if !ok {
+			continue
+		}
+		if options.Placement == DefaultsSchemaOrder {
+			if index := insertIndex(node, properties, order); index >= 0 {
+				if err := node.SetForceAt(index, name, value); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if err := node.SetForce(name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// defaultValue returns a copy of the default of schema, or a mapping of the defaults of its properties.
+func (s *Schema) defaultValue(schema interface{}) (interface{}, bool) {
+	object := s.schemaObject(schema)
+	if object == nil {
+		return nil, false
+	}
+	if value, ok := schemaKeyword(object, "default"); ok {
+		return cloneValue(value), true
+	}
+	mapSlice := yaml.MapSlice{}
+	for _, property := range plainMapping(lookupKeyword(object, "properties")) {
+		if value, ok := s.defaultValue(property.Value); ok {
+			mapSlice = append(mapSlice, yaml.MapItem{Key: property.Key, Value: value})
+		}
+	}
+	if len(mapSlice) == 0 {
+		return nil, false
+	}
+	return &mapSlice, true
+}
+
+// schemaObject returns schema as an object, following $ref, or nil if it is a boolean schema.
+func (s *Schema) schemaObject(schema interface{}) yaml.MapSlice {
+	for depth := 0; depth < maxReferenceDepth; depth++ {
+		object, ok := plainValue(schema).(yaml.MapSlice)
+		if !ok {
+			return nil
+		}
+		reference, ok := schemaKeyword(object, "$ref")
+		if !ok {
+			return object
+		}
+		if schema, ok = s.resolveReference(reference); !ok {
+			return object
+		}
+	}
+	return nil
+}
+
+// insertIndex returns the index of the first key of node which follows the property at order in properties, or -1.
+func insertIndex(node *RoughYaml, properties yaml.MapSlice, order int) int {
+	mapSlice, _ := plainValue(node.GetContents()).(yaml.MapSlice)
+	for index, item := range mapSlice {
+		if position := findKeyIndex(properties, item.Key); position > order {
+			return index
+		}
+	}
+	return -1
+}
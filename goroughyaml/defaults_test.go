@@ -0,0 +1,106 @@
+package goroughyaml
+
+import (
+	"testing"
+)
+
+func TestApplyDefaults(t *testing.T) {
+	//---------------------
+	// init
+	schema, err := LoadSchema(`
+type: object
+properties:
+  name: {type: string, default: app}
+  server:
+    $ref: "#/$defs/server"
+  log:
+    properties:
+      level: {default: info}
+      format: {default: text}
+  workers:
+    items:
+      properties:
+        name: {type: string}
+        threads: {default: 1}
+  debug: {default: false}
+$defs:
+  server:
+    properties:
+      host: {default: localhost}
+      port: {default: 8080}
+      tls: {type: boolean}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	yamlString := `
+custom: value
+debug: true
+server:
+  port: 9090
+workers:
+- name: aaa
+- name: bbb
+  threads: 4
+`
+	var expectedValue interface{}
+	var actualValue interface{}
+
+	//
+	//
+	//---------------------
+	// success (append)
+	roughYamlObj := FromYaml(yamlString)
+	if err := roughYamlObj.ApplyDefaults(schema); err != nil {
+		t.Errorf("<< FAILED >>> : %v", err)
+	}
+	expectedValue = `custom: value
+debug: true
+server:
+  port: 9090
+  host: localhost
+workers:
+- name: aaa
+  threads: 1
+- name: bbb
+  threads: 4
+name: app
+log:
+  level: info
+  format: text
+`
+	actualValue, _ = roughYamlObj.ToYaml()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+
+	//
+	//
+	//---------------------
+	// success (schema order)
+	roughYamlObj = FromYaml(yamlString)
+	if err := roughYamlObj.ApplyDefaultsWithOptions(schema, DefaultsOptions{Placement: DefaultsSchemaOrder}); err != nil {
+		t.Errorf("<< FAILED >>> : %v", err)
+	}
+	expectedValue = `custom: value
+name: app
+log:
+  level: info
+  format: text
+debug: true
+server:
+  host: localhost
+  port: 9090
+workers:
+- name: aaa
+  threads: 1
+- name: bbb
+  threads: 4
+`
+	actualValue, _ = roughYamlObj.ToYaml()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+}
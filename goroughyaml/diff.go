@@ -0,0 +1,81 @@
+package goroughyaml
+
+import (
+	"gopkg.in/yaml.v2"
+)
+
+// Diff returns the changes which turn the document of a into the document of b.
+// Mappings are compared key by key, and so are sequences of the same length;
+// a sequence which changes its length is reported as replaced as a whole.
+// When the keys which a mapping has in both documents are in a different order,
+// an OperationReorder change for the mapping follows the changes of its keys.
+func Diff(a *RoughYaml, b *RoughYaml) []Change {
+	return diffValues(nil, a.GetContents(), b.GetContents(), nil)
+}
+
+func diffValues(path []string, a interface{}, b interface{}, changes []Change) []Change {
+	a = plainValue(a)
+	b = plainValue(b)
+	aMapSlice, aIsMapping := a.(yaml.MapSlice)
+	bMapSlice, bIsMapping := b.(yaml.MapSlice)
+	if aIsMapping && bIsMapping {
+		return diffMappings(path, aMapSlice, bMapSlice, changes)
+	}
+	aSlice, aIsSequence := a.([]interface{})
+	bSlice, bIsSequence := b.([]interface{})
+	if aIsSequence && bIsSequence && len(aSlice) == len(bSlice) {
+		for index := range aSlice {
+			changes = diffValues(appendPath(path, keyString(index)), aSlice[index], bSlice[index], changes)
+		}
+		return changes
+	}
+	if !valuesEqual(a, b, EqualOptions{}) {
+		changes = append(changes, Change{Operation: OperationReplace, Path: path, OldValue: a, NewValue: b})
+	}
+	return changes
+}
+
+func diffMappings(path []string, a yaml.MapSlice, b yaml.MapSlice, changes []Change) []Change {
+	var aOrder []interface{}
+	for _, item := range a {
+		index := findKeyIndex(b, item.Key)
+		if index < 0 {
+			changes = append(changes, Change{Operation: OperationDelete, Path: appendPath(path, keyString(item.Key)), OldValue: item.Value})
+			continue
+		}
+		aOrder = append(aOrder, b[index].Key)
+		changes = diffValues(appendPath(path, keyString(item.Key)), item.Value, b[index].Value, changes)
+	}
+	var bOrder []interface{}
+	for _, item := range b {
+		if findKeyIndex(a, item.Key) < 0 {
+			changes = append(changes, Change{Operation: OperationAdd, Path: appendPath(path, keyString(item.Key)), NewValue: item.Value})
+			continue
+		}
+		bOrder = append(bOrder, item.Key)
+	}
+	for index := 0; index < len(aOrder) && index < len(bOrder); index++ {
+		if !keysEqual(aOrder[index], bOrder[index]) {
+			changes = append(changes, Change{Operation: OperationReorder, Path: path, OldValue: a, NewValue: b})
+			break
+		}
+	}
+	return changes
+}
+
+// plainValue dereferences the pointers which a document holds for mappings and scalars.
+func plainValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *yaml.MapSlice:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *interface{}:
+		if v == nil {
+			return nil
+		}
+		return plainValue(*v)
+	}
+	return value
+}
@@ -0,0 +1,76 @@
+package goroughyaml
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	//---------------------
+	// init
+	roughYamlObj1 := FromYaml(`
+aaa:
+  bbb: bbb1
+  ccc: ccc1
+  ddd:
+  - 1
+  - 2
+eee: eee1
+fff: [1]
+`)
+	roughYamlObj2 := FromYaml(`
+aaa:
+  ccc: ccc2
+  bbb: bbb1
+  ddd:
+  - 1
+  - 3
+  ggg: ggg1
+fff: [1, 2]
+`)
+	var expectedValue interface{}
+	var actualValue interface{}
+
+	//
+	//
+	//---------------------
+	// success (changes in document order)
+	expectedValue = []string{
+		"replace aaa.ccc",
+		"replace aaa.ddd.1",
+		"add aaa.ggg",
+		"reorder aaa",
+		"delete eee",
+		"replace fff",
+	}
+	var descriptions []string
+	for _, change := range Diff(&roughYamlObj1, &roughYamlObj2) {
+		descriptions = append(descriptions, fmt.Sprintf("%v %v", change.Operation, strings.Join(change.Path, ".")))
+	}
+	actualValue = descriptions
+	if !reflect.DeepEqual(actualValue, expectedValue) {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+
+	//
+	//
+	//---------------------
+	// success (values)
+	change := Diff(&roughYamlObj1, &roughYamlObj2)[0]
+	if change.OldValue != "ccc1" || change.NewValue != "ccc2" {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("change:%v\n", change)
+	}
+
+	//
+	//
+	//---------------------
+	// success (no changes)
+	if changes := Diff(&roughYamlObj1, roughYamlObj1.Clone()); len(changes) != 0 {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("changes:%v\n", changes)
+	}
+}
@@ -0,0 +1,508 @@
+package goroughyaml
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ListIndentStyle controls how a sequence nested in a mapping is indented.
+type ListIndentStyle int
+
+const (
+	// ListIndentFlush writes the entries of a sequence at the column of its parent key (yaml.v2 style).
+	ListIndentFlush ListIndentStyle = iota
+	// ListIndentIndented writes the entries of a sequence one indentation level deeper than its parent key.
+	ListIndentIndented
+)
+
+// QuoteStyle controls how string values are quoted.
+type QuoteStyle int
+
+const (
+	// QuoteAsNeeded quotes a string only when it cannot be written as a plain scalar (yaml.v2 style).
+	QuoteAsNeeded QuoteStyle = iota
+	// QuoteSingle writes every string value in single quotes.
+	QuoteSingle
+	// QuoteDouble writes every string value in double quotes.
+	QuoteDouble
+)
+
+// NodeStyle is the style of a mapping or a sequence.
+type NodeStyle int
+
+const (
+	// BlockStyle writes a collection one entry per line.
+	BlockStyle NodeStyle = iota
+	// FlowStyle writes a collection on one line, like {a: 1, b: [x, y]}.
+	FlowStyle
+)
+
+// YamlOptions configures ToYamlWithOptions.
+// The zero value produces the same layout as ToYaml.
+type YamlOptions struct {
+	// Indent is the number of spaces per indentation level. 0 means 2.
+	Indent int
+	// ListIndent is the indentation of sequences nested in a mapping.
+	ListIndent ListIndentStyle
+	// LineWidth is the column at which long strings are folded. 0 means 80, a negative value disables folding.
+	LineWidth int
+	// QuoteStyle is the quoting of string values. Mapping keys are always quoted as needed.
+	QuoteStyle QuoteStyle
+	// Style returns the style of the collection at path. nil means every collection is written in BlockStyle.
+	// The path holds the keys from the node ToYamlWithOptions is called on, sequence indexes as strings.
+	Style func(path []string, value interface{}) NodeStyle
+	// DocumentStart writes a "---" line before the document.
+	DocumentStart bool
+	// DocumentEnd writes a "..." line after the document.
+	DocumentEnd bool
+}
+
+// ToYamlWithOptions returns the node as yaml, laid out according to options.
+func (o *RoughYaml) ToYamlWithOptions(options YamlOptions) (string, error) {
+	e := newEmitter(options)
+	if err := e.emitDocument(o.GetContents()); err != nil {
+		return "", err
+	}
+	return e.buf.String(), nil
+}
+
+type emitter struct {
+	options YamlOptions
+	buf     strings.Builder
+}
+
+func newEmitter(options YamlOptions) *emitter {
+	if options.Indent <= 0 {
+		options.Indent = 2
+	}
+	if options.LineWidth == 0 {
+		options.LineWidth = 80
+	}
+	return &emitter{options: options}
+}
+
+func (e *emitter) emitDocument(value interface{}) error {
+	if e.options.DocumentStart {
+		e.buf.WriteString("---\n")
+	}
+	if err := e.emitValue(value, -1, -1, []string{}); err != nil {
+		return err
+	}
+	if e.options.DocumentEnd {
+		e.buf.WriteString("...\n")
+	}
+	return nil
+}
+
+// emitValue writes value followed by a newline.
+// col is the column of the parent key or sequence entry, and -1 for the document root.
+func (e *emitter) emitValue(value interface{}, col int, inlineCol int, path []string) error {
+	value, err := normalizeValue(value)
+	if err != nil {
+		return err
+	}
+	childCol := col + e.options.Indent
+	scalarCol := childCol
+	if col < 0 {
+		childCol = 0
+		scalarCol = e.options.Indent
+	}
+	switch v := value.(type) {
+	case yaml.MapSlice:
+		if len(v) > 0 && e.style(path, v) == BlockStyle {
+			if inlineCol >= 0 {
+				e.buf.WriteString("\n")
+			}
+			return e.emitMapping(v, childCol, false, path)
+		}
+	case []interface{}:
+		if len(v) > 0 && e.style(path, v) == BlockStyle {
+			if inlineCol >= 0 {
+				e.buf.WriteString("\n")
+			}
+			if col >= 0 && e.options.ListIndent == ListIndentFlush {
+				childCol = col
+			}
+			return e.emitSequence(v, childCol, false, path)
+		}
+	}
+	if inlineCol >= 0 {
+		e.buf.WriteString(" ")
+		inlineCol++
+	} else {
+		inlineCol = 0
+	}
+	return e.emitInline(value, inlineCol, scalarCol, scalarCol-col, path)
+}
+
+// emitInline writes a scalar or a flow collection starting at column inlineCol, followed by a newline.
+// Continuation lines of a string are indented to childCol, which is relIndent deeper than the parent node.
+func (e *emitter) emitInline(value interface{}, inlineCol int, childCol int, relIndent int, path []string) error {
+	switch v := value.(type) {
+	case yaml.MapSlice, []interface{}:
+		text, err := e.flow(v, path)
+		if err != nil {
+			return err
+		}
+		e.buf.WriteString(text)
+	case string:
+		e.buf.WriteString(e.stringValue(v, inlineCol, childCol, relIndent))
+	default:
+		text, err := scalarText(v)
+		if err != nil {
+			return err
+		}
+		e.buf.WriteString(text)
+	}
+	e.buf.WriteString("\n")
+	return nil
+}
+
+func (e *emitter) emitMapping(mapSlice yaml.MapSlice, col int, inline bool, path []string) error {
+	for index, item := range mapSlice {
+		if index > 0 || !inline {
+			e.buf.WriteString(strings.Repeat(" ", col))
+		}
+		key, err := e.key(item.Key)
+		if err != nil {
+			return err
+		}
+		e.buf.WriteString(key)
+		e.buf.WriteString(":")
+		childPath := appendPath(path, keyString(item.Key))
+		if err := e.emitValue(item.Value, col, col+utf8.RuneCountInString(key)+1, childPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *emitter) emitSequence(slice []interface{}, col int, inline bool, path []string) error {
+	for index, item := range slice {
+		if index > 0 || !inline {
+			e.buf.WriteString(strings.Repeat(" ", col))
+		}
+		e.buf.WriteString("- ")
+		childPath := appendPath(path, strconv.Itoa(index))
+		value, err := normalizeValue(item)
+		if err != nil {
+			return err
+		}
+		switch v := value.(type) {
+		case yaml.MapSlice:
+			if len(v) > 0 && e.style(childPath, v) == BlockStyle {
+				if err := e.emitMapping(v, col+2, true, childPath); err != nil {
+					return err
+				}
+				continue
+			}
+		case []interface{}:
+			if len(v) > 0 && e.style(childPath, v) == BlockStyle {
+				if err := e.emitSequence(v, col+2, true, childPath); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if err := e.emitInline(value, col+2, col+2, 2, childPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *emitter) style(path []string, value interface{}) NodeStyle {
+	if e.options.Style == nil {
+		return BlockStyle
+	}
+	return e.options.Style(path, value)
+}
+
+func (e *emitter) flow(value interface{}, path []string) (string, error) {
+	value, err := normalizeValue(value)
+	if err != nil {
+		return "", err
+	}
+	switch v := value.(type) {
+	case yaml.MapSlice:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			key, err := e.key(item.Key)
+			if err != nil {
+				return "", err
+			}
+			text, err := e.flow(item.Value, appendPath(path, keyString(item.Key)))
+			if err != nil {
+				return "", err
+			}
+			items = append(items, key+": "+text)
+		}
+		return "{" + strings.Join(items, ", ") + "}", nil
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for index, item := range v {
+			text, err := e.flow(item, appendPath(path, strconv.Itoa(index)))
+			if err != nil {
+				return "", err
+			}
+			items = append(items, text)
+		}
+		return "[" + strings.Join(items, ", ") + "]", nil
+	case string:
+		return e.quote(v, e.options.QuoteStyle, true), nil
+	}
+	return scalarText(value)
+}
+
+func (e *emitter) key(key interface{}) (string, error) {
+	key, err := normalizeValue(key)
+	if err != nil {
+		return "", err
+	}
+	switch k := key.(type) {
+	case yaml.MapSlice, []interface{}:
+		return e.flow(k, nil)
+	case string:
+		return e.quote(k, QuoteAsNeeded, false), nil
+	}
+	return scalarText(key)
+}
+
+// stringValue returns a string value starting at column col, folded at the line width.
+// Continuation lines and literal block contents are indented to childCol.
+func (e *emitter) stringValue(s string, col int, childCol int, relIndent int) string {
+	if e.options.QuoteStyle == QuoteAsNeeded && naturalStyle(s) == '|' {
+		return literalBlock(s, childCol, relIndent)
+	}
+	return e.fold(e.quote(s, e.options.QuoteStyle, false), col, childCol)
+}
+
+func (e *emitter) quote(s string, style QuoteStyle, inFlow bool) string {
+	if needsEscape(s) {
+		return doubleQuoted(s)
+	}
+	switch style {
+	case QuoteSingle:
+		return singleQuoted(s)
+	case QuoteDouble:
+		return doubleQuoted(s)
+	}
+	switch naturalStyle(s) {
+	case '"', '|':
+		return doubleQuoted(s)
+	case '\'':
+		return singleQuoted(s)
+	}
+	if inFlow && strings.ContainsAny(s, ",[]{}") {
+		return singleQuoted(s)
+	}
+	return s
+}
+
+// fold breaks text at the first single space after each line passes the line width, as yaml.v2 does.
+func (e *emitter) fold(text string, col int, childCol int) string {
+	width := e.options.LineWidth
+	if width < 0 || col+utf8.RuneCountInString(text) <= width {
+		return text
+	}
+	var b strings.Builder
+	lineStart := true
+	for _, word := range splitFoldable(text) {
+		length := utf8.RuneCountInString(word)
+		if !lineStart && col > width {
+			b.WriteString("\n")
+			b.WriteString(strings.Repeat(" ", childCol))
+			col = childCol
+			lineStart = true
+		}
+		if !lineStart {
+			b.WriteString(" ")
+			col++
+		}
+		b.WriteString(word)
+		col += length
+		lineStart = false
+	}
+	return b.String()
+}
+
+// splitFoldable splits text at the spaces that can be replaced by a line break,
+// which are single spaces between two other characters.
+func splitFoldable(text string) []string {
+	words := make([]string, 0)
+	start := 0
+	for i := 1; i < len(text)-1; i++ {
+		if text[i] == ' ' && text[i-1] != ' ' && text[i+1] != ' ' && i > start {
+			words = append(words, text[start:i])
+			start = i + 1
+		}
+	}
+	return append(words, text[start:])
+}
+
+// naturalStyle returns the first character of s as yaml.v2 writes it:
+// a double or single quote for a quoted string, '|' for a literal block, and 0 for a plain scalar.
+func naturalStyle(s string) byte {
+	bytes, err := yaml.Marshal(s)
+	if err != nil || len(bytes) == 0 {
+		return '"'
+	}
+	switch bytes[0] {
+	case '"', '\'', '|':
+		return bytes[0]
+	case '>':
+		return '"'
+	}
+	return 0
+}
+
+// needsEscape reports whether s holds a character that only a double-quoted scalar can represent.
+func needsEscape(s string) bool {
+	for _, r := range s {
+		if r == '\t' {
+			continue
+		}
+		if r < 0x20 || r == 0x7f || r == utf8.RuneError || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
+
+func singleQuoted(s string) string {
+	return "'" + strings.Replace(s, "'", "''", -1) + "'"
+}
+
+func doubleQuoted(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\r':
+			b.WriteString(`\r`)
+		case 0:
+			b.WriteString(`\0`)
+		default:
+			if r < 0x20 || r == 0x7f || !unicode.IsPrint(r) && r != ' ' {
+				if r <= 0xff {
+					fmt.Fprintf(&b, `\x%02X`, r)
+				} else if r > 0xffff {
+					fmt.Fprintf(&b, `\U%08X`, r)
+				} else {
+					fmt.Fprintf(&b, `\u%04X`, r)
+				}
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+func literalBlock(s string, childCol int, relIndent int) string {
+	header := "|-"
+	body := s
+	if strings.HasSuffix(s, "\n") {
+		body = strings.TrimRight(s, "\n")
+		header = "|"
+		if len(s)-len(body) > 1 {
+			header = "|+"
+		}
+	}
+	if strings.HasPrefix(s, " ") {
+		header = header[:1] + strconv.Itoa(relIndent) + header[1:]
+	}
+	var b strings.Builder
+	b.WriteString(header)
+	lines := strings.Split(body, "\n")
+	if header[len(header)-1] == '+' {
+		lines = append(lines, make([]string, len(s)-len(body)-1)...)
+	}
+	for _, line := range lines {
+		b.WriteString("\n")
+		if line != "" {
+			b.WriteString(strings.Repeat(" ", childCol))
+			b.WriteString(line)
+		}
+	}
+	return b.String()
+}
+
+// scalarText returns a non-string scalar as yaml.v2 writes it.
+func scalarText(value interface{}) (string, error) {
+	if value == nil {
+		return "null", nil
+	}
+	bytes, err := yaml.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(bytes), "\n"), nil
+}
+
+// normalizeValue dereferences pointers and converts maps, structs and typed slices
+// so that a value is a yaml.MapSlice, an []interface{} or a scalar.
+func normalizeValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case nil, yaml.MapSlice, []interface{}, string, bool, int, int64, uint64, float64, time.Time:
+		return v, nil
+	case *yaml.MapSlice:
+		if v == nil {
+			return nil, nil
+		}
+		return *v, nil
+	case *interface{}:
+		if v == nil {
+			return nil, nil
+		}
+		return normalizeValue(*v)
+	}
+	reflected := reflect.ValueOf(value)
+	switch reflected.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if reflected.IsNil() {
+			return nil, nil
+		}
+		return normalizeValue(reflected.Elem().Interface())
+	case reflect.Slice, reflect.Array:
+		slice := make([]interface{}, reflected.Len())
+		for i := range slice {
+			slice[i] = reflected.Index(i).Interface()
+		}
+		return slice, nil
+	case reflect.Map, reflect.Struct:
+		return convertViaYaml(value)
+	}
+	return value, nil
+}
+
+// convertViaYaml converts a value to the types yaml.v2 decodes into a yaml.MapSlice.
+func convertViaYaml(value interface{}) (interface{}, error) {
+	bytes, err := yaml.Marshal(yaml.MapSlice{yaml.MapItem{Key: "value", Value: value}})
+	if err != nil {
+		return nil, err
+	}
+	mapSlice := yaml.MapSlice{}
+	if err := yaml.Unmarshal(bytes, &mapSlice); err != nil {
+		return nil, err
+	}
+	if len(mapSlice) == 0 {
+		return nil, nil
+	}
+	return mapSlice[0].Value, nil
+}
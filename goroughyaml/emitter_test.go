@@ -0,0 +1,155 @@
+package goroughyaml
+
+import (
+	"testing"
+)
+
+func TestToYamlWithOptions(t *testing.T) {
+	//---------------------
+	// init
+	yamlString := `
+aaa:
+  bbb:
+    bbb1: bbb
+    bbb2: 111
+    "111": "true"
+  ccc:
+  - 1
+  - 2
+  eee:
+  - aaa: aaa1
+    bbb:
+    - bbb1
+    - - ccc1
+      - ccc2
+  fff: |-
+    line1
+    line2
+  ggg: {}
+`
+	var expectedValue interface{}
+	var actualValue interface{}
+
+	roughYamlObj := FromYaml(yamlString)
+
+	//
+	//
+	//---------------------
+	// success (zero value is the same as ToYaml)
+	expectedValue, _ = roughYamlObj.ToYaml()
+	actualValue, _ = roughYamlObj.ToYamlWithOptions(YamlOptions{})
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+
+	//
+	//
+	//---------------------
+	// success (indent, list indent, quote style and document markers)
+	expectedValue = `---
+aaa:
+    bbb:
+        bbb1: "bbb"
+        bbb2: 111
+        "111": "true"
+    ccc:
+        - 1
+        - 2
+    eee:
+        - aaa: "aaa1"
+          bbb:
+              - "bbb1"
+              - - "ccc1"
+                - "ccc2"
+    fff: "line1\nline2"
+    ggg: {}
+...
+`
+	actualValue, _ = roughYamlObj.ToYamlWithOptions(YamlOptions{
+		Indent:        4,
+		ListIndent:    ListIndentIndented,
+		QuoteStyle:    QuoteDouble,
+		DocumentStart: true,
+		DocumentEnd:   true,
+	})
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+
+	//
+	//
+	//---------------------
+	// success (flow style)
+	expectedValue = `aaa:
+  bbb: {bbb1: 'bbb', bbb2: 111, "111": 'true'}
+  ccc: [1, 2]
+  eee:
+  - aaa: 'aaa1'
+    bbb: ['bbb1', ['ccc1', 'ccc2']]
+  fff: "line1\nline2"
+  ggg: {}
+`
+	actualValue, _ = roughYamlObj.ToYamlWithOptions(YamlOptions{
+		QuoteStyle: QuoteSingle,
+		Style: func(path []string, value interface{}) NodeStyle {
+			if len(path) == 2 && path[1] != "eee" || len(path) == 4 {
+				return FlowStyle
+			}
+			return BlockStyle
+		},
+	})
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+}
+
+func TestToYamlWithOptionsLineWidth(t *testing.T) {
+	//---------------------
+	// init
+	yamlString := `
+aaa:
+  bbb: aaaa bbbb cccc dddd eeee
+  ccc:
+  - aaaa bbbb cccc dddd
+`
+	var expectedValue interface{}
+	var actualValue interface{}
+
+	roughYamlObj := FromYaml(yamlString)
+
+	//
+	//
+	//---------------------
+	// success (folded)
+	expectedValue = `aaa:
+  bbb: aaaa bbbb
+    cccc dddd eeee
+  ccc:
+  - aaaa bbbb cccc
+    dddd
+`
+	actualValue, _ = roughYamlObj.ToYamlWithOptions(YamlOptions{LineWidth: 15})
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+	folded := FromYaml(actualValue.(string))
+	if folded.Get("aaa").Get("bbb").Value() != "aaaa bbbb cccc dddd eeee" {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v\n", folded.Get("aaa").Get("bbb").Value())
+	}
+
+	//
+	//
+	//---------------------
+	// success (not folded)
+	expectedValue, _ = roughYamlObj.ToYaml()
+	actualValue, _ = roughYamlObj.ToYamlWithOptions(YamlOptions{LineWidth: -1})
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+}
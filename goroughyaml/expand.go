@@ -0,0 +1,208 @@
+package goroughyaml
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"os"
+	"strings"
+)
+
+// ExpandOptions configures ExpandEnvWithOptions.
+type ExpandOptions struct {
+	// CoerceTypes parses the result as yaml when the whole string is one placeholder,
+	// so that "${PORT}" with PORT=8080 becomes the number 8080 instead of the string "8080".
+	CoerceTypes bool
+}
+
+// ExpandError is a placeholder which ExpandEnv or ResolveReferences could not expand.
+type ExpandError struct {
+	Path    []string
+	Message string
+}
+
+func (e ExpandError) Error() string {
+	return fmt.Sprintf("%v: %v", describePath(e.Path), e.Message)
+}
+
+// ExpandErrors is the list of the errors of ExpandEnv or ResolveReferences.
+type ExpandErrors []ExpandError
+
+func (e ExpandErrors) Error() string {
+	messages := make([]string, len(e))
+	for index, err := range e {
+		messages[index] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// ExpandEnv replaces placeholders for variables in the string values below the node.
+// lookup returns the value of a variable and whether it is set; nil means os.LookupEnv.
+//
+//	${NAME}          the value of NAME, or "" if it is not set
+//	${NAME:-default} default if NAME is not set or empty
+//	${NAME-default}  default if NAME is not set
+//	${NAME:?message} an error with message if NAME is not set or empty
+//	${NAME?message}  an error with message if NAME is not set
+//	$$               a literal $
+//
+// NAME consists of letters, digits and '_' and does not start with a digit.
+// Other text, such as a "${.a.b}" reference, is left as it is, and so is "$${." so that ResolveReferences can unescape it.
+// Mapping keys are not expanded.
+// If any placeholder fails, ExpandEnv changes nothing and returns an ExpandErrors with all the failures.
+func (o *RoughYaml) ExpandEnv(lookup func(name string) (string, bool)) error {
+	return o.ExpandEnvWithOptions(lookup, ExpandOptions{})
+}
+
+// ExpandEnvWithOptions is ExpandEnv configured by options.
+func (o *RoughYaml) ExpandEnvWithOptions(lookup func(name string) (string, bool), options ExpandOptions) error {
+	if o.err != nil {
+		return o.err
+	}
+	if lookup == nil {
+		lookup = os.LookupEnv
+	}
+	var expansions []expansion
+	var errs ExpandErrors
+	o.walkStrings(func(parent *RoughYaml, key interface{}, text string) {
+		expanded, whole, err := expandString(text, lookup)
+		if err != nil {
+			errs = append(errs, ExpandError{Path: appendPath(parent.path, keyString(key)), Message: err.Error()})
+			return
+		}
+		if expanded == text {
+			return
+		}
+		var value interface{} = expanded
+		if whole && options.CoerceTypes {
+			value = parseYamlValue(expanded)
+		}
+		expansions = append(expansions, expansion{parent: parent, key: key, value: value})
+	})
+	if len(errs) > 0 {
+		return errs
+	}
+	for _, e := range expansions {
+		if err := e.parent.SetKey(e.key, e.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expansion is a new value for key of parent.
+type expansion struct {
+	parent *RoughYaml
+	key    interface{}
+	value  interface{}
+}
+
+// walkStrings calls fn for each string value below the node with its parent node and key.
+func (o *RoughYaml) walkStrings(fn func(parent *RoughYaml, key interface{}, text string)) {
+	var keys []interface{}
+	switch v := plainValue(o.GetContents()).(type) {
+	case yaml.MapSlice:
+		for _, item := range v {
+			keys = append(keys, item.Key)
+		}
+	case []interface{}:
+		for index := range v {
+			keys = append(keys, index)
+		}
+	}
+	for _, key := range keys {
+		child := o.GetKey(key)
+		switch value := plainValue(child.Value()).(type) {
+		case string:
+			fn(o, key, value)
+		case yaml.MapSlice, []interface{}:
+			child.walkStrings(fn)
+		}
+	}
+}
+
+// expandString expands the placeholders in text. whole reports whether text is one placeholder.
+func expandString(text string, lookup func(name string) (string, bool)) (string, bool, error) {
+	builder := &strings.Builder{}
+	whole := false
+	for index := 0; index < len(text); {
+		if text[index] != '$' || index+1 >= len(text) {
+			builder.WriteByte(text[index])
+			index++
+			continue
+		}
+		if text[index+1] == '$' {
+			if strings.HasPrefix(text[index+2:], "{.") {
+				builder.WriteString("$$")
+			} else {
+				builder.WriteByte('$')
+			}
+			index += 2
+			continue
+		}
+		end := strings.IndexByte(text[index:], '}')
+		if text[index+1] != '{' || end < 0 {
+			builder.WriteByte('$')
+			index++
+			continue
+		}
+		body := text[index+2 : index+end]
+		name, operator, argument := splitPlaceholder(body)
+		if name == "" {
+			builder.WriteString(text[index : index+end+1])
+			index += end + 1
+			continue
+		}
+		value, ok := lookup(name)
+		switch operator {
+		case ":-":
+			if !ok || value == "" {
+				value = argument
+			}
+		case "-":
+			if !ok {
+				value = argument
+			}
+		case ":?", "?":
+			if !ok || (operator == ":?" && value == "") {
+				if argument == "" {
+					argument = "is not set"
+					if operator == ":?" {
+						argument = "is not set or empty"
+					}
+					return "", false, fmt.Errorf("%v %v", name, argument)
+				}
+				return "", false, fmt.Errorf("%v: %v", name, argument)
+			}
+		}
+		whole = index == 0 && end+1 == len(text)
+		builder.WriteString(value)
+		index += end + 1
+	}
+	return builder.String(), whole, nil
+}
+
+// splitPlaceholder splits the body of ${...} into the name, the operator and its argument.
+// It returns an empty name if body does not start with a valid name.
+func splitPlaceholder(body string) (string, string, string) {
+	length := 0
+	for length < len(body) && isNameByte(body[length], length == 0) {
+		length++
+	}
+	if length == 0 {
+		return "", "", ""
+	}
+	rest := body[length:]
+	for _, operator := range []string{":-", ":?", "-", "?"} {
+		if strings.HasPrefix(rest, operator) {
+			return body[:length], operator, rest[len(operator):]
+		}
+	}
+	if rest != "" {
+		return "", "", ""
+	}
+	return body, "", ""
+}
+
+func isNameByte(b byte, first bool) bool {
+	return b == '_' || ('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z') || (!first && '0' <= b && b <= '9')
+}
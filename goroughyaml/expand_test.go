@@ -0,0 +1,87 @@
+package goroughyaml
+
+import (
+	"testing"
+)
+
+func TestExpandEnv(t *testing.T) {
+	//---------------------
+	// init
+	yamlString := `
+database:
+  host: ${DB_HOST}
+  port: ${PORT:-8080}
+  url: postgres://${DB_HOST}:${PORT-5432}/app
+hosts:
+- ${DB_HOST}
+- $${DB_HOST} costs $$5
+- ${.database.host} and $${.database.host}
+`
+	env := map[string]string{"DB_HOST": "db.example.com", "PORT": ""}
+	lookup := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+	var expectedValue interface{}
+	var actualValue interface{}
+
+	//
+	//
+	//---------------------
+	// success (expand)
+	roughYamlObj := FromYaml(yamlString)
+	if err := roughYamlObj.ExpandEnv(lookup); err != nil {
+		t.Errorf("<< FAILED >>> : %v", err)
+	}
+	expectedValue = `database:
+  host: db.example.com
+  port: "8080"
+  url: postgres://db.example.com:/app
+hosts:
+- db.example.com
+- ${DB_HOST} costs $5
+- ${.database.host} and $${.database.host}
+`
+	actualValue, _ = roughYamlObj.ToYaml()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+
+	//
+	//
+	//---------------------
+	// success (coerce types of whole placeholders)
+	roughYamlObj = FromYaml(yamlString)
+	roughYamlObj.ExpandEnvWithOptions(lookup, ExpandOptions{CoerceTypes: true})
+	expectedValue = 8080
+	actualValue = roughYamlObj.Get("database").Get("port").Value()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+	}
+	t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+
+	//
+	//
+	//---------------------
+	// error (all required variables are reported and nothing is changed)
+	roughYamlObj = FromYaml(`
+aaa: ${SECRET:?required}
+bbb:
+- ${PORT:?}
+- ${DB_HOST}
+`)
+	err := roughYamlObj.ExpandEnv(lookup)
+	expectedValue = `'aaa': SECRET: required
+'bbb.0': PORT is not set or empty`
+	if err == nil || err.Error() != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", err, expectedValue)
+	}
+	expectedValue = "${DB_HOST}"
+	actualValue = roughYamlObj.Get("bbb").Get("1").Value()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+	}
+	t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+}
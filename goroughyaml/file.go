@@ -0,0 +1,129 @@
+package goroughyaml
+
+import (
+	"crypto/sha256"
+	"errors"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// ErrFileModified is returned by SaveFileWithOptions when the file has been changed on disk since it was loaded.
+var ErrFileModified = errors.New("goroughyaml: file has been modified since it was loaded")
+
+// SaveOptions configures SaveFileWithOptions.
+type SaveOptions struct {
+	// FailIfModified refuses to write when the file has been changed on disk since the node was loaded from it by LoadFile.
+	FailIfModified bool
+	// Perm is the permission of a newly created file. 0 means 0644. An existing file keeps its permission.
+	Perm os.FileMode
+	// YamlOptions lays out the file as ToYamlWithOptions does. nil means the layout of ToYaml.
+	YamlOptions *YamlOptions
+}
+
+type loadedFile struct {
+	path     string
+	checksum [sha256.Size]byte
+}
+
+// LoadFile reads the yaml file at path.
+func LoadFile(path string) (RoughYaml, error) {
+	bytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return newRoughYaml(&yaml.MapSlice{}), err
+	}
+	return loadBytes(path, bytes)
+}
+
+// loadBytes parses bytes, which were read from the file at path.
+func loadBytes(path string, bytes []byte) (RoughYaml, error) {
+	mapSlice := &yaml.MapSlice{}
+	if err := yaml.Unmarshal(bytes, mapSlice); err != nil {
+		return newRoughYaml(&yaml.MapSlice{}), err
+	}
+	loaded := newRoughYaml(mapSlice)
+	loaded.file = &loadedFile{path: absPath(path), checksum: sha256.Sum256(bytes)}
+	loaded.doc.text = string(bytes)
+	return loaded, nil
+}
+
+// SaveFile writes the node to the file at path.
+// The file is replaced atomically and keeps its permission and, where possible, its owner.
+func (o *RoughYaml) SaveFile(path string) error {
+	return o.SaveFileWithOptions(path, SaveOptions{})
+}
+
+// SaveFileWithOptions writes the node to the file at path according to options.
+func (o *RoughYaml) SaveFileWithOptions(path string, options SaveOptions) error {
+	var content string
+	var err error
+	if options.YamlOptions != nil {
+		content, err = o.ToYamlWithOptions(*options.YamlOptions)
+	} else {
+		content, err = o.ToYaml()
+	}
+	if err != nil {
+		return err
+	}
+	if options.FailIfModified && o.file != nil && o.file.path == absPath(path) {
+		bytes, err := ioutil.ReadFile(path)
+		if err != nil || sha256.Sum256(bytes) != o.file.checksum {
+			return ErrFileModified
+		}
+	}
+	perm := options.Perm
+	if perm == 0 {
+		perm = 0644
+	}
+	if err := writeFileAtomic(path, []byte(content), perm); err != nil {
+		return err
+	}
+	o.file = &loadedFile{path: absPath(path), checksum: sha256.Sum256([]byte(content))}
+	return nil
+}
+
+// writeFileAtomic writes data to a temporary file in the directory of path and renames it to path,
+// so that readers see either the old or the new content.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) (err error) {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	info, statErr := os.Stat(path)
+	tmp, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(tmp.Name())
+		}
+	}()
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if statErr == nil {
+		perm = info.Mode() & (os.ModePerm | os.ModeSetuid | os.ModeSetgid | os.ModeSticky)
+		preserveOwner(tmp.Name(), info)
+	}
+	if err = os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func absPath(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	return abs
+}
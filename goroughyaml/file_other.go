@@ -0,0 +1,12 @@
+//go:build windows || plan9
+// +build windows plan9
+
+package goroughyaml
+
+import (
+	"os"
+)
+
+// preserveOwner does nothing on platforms without unix file ownership.
+func preserveOwner(path string, info os.FileInfo) {
+}
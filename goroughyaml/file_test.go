@@ -0,0 +1,103 @@
+package goroughyaml
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadFileAndSaveFile(t *testing.T) {
+	//---------------------
+	// init
+	yamlString := `aaa:
+  bbb: bbb1
+  ccc:
+  - 1
+`
+	dir, err := ioutil.TempDir("", "goroughyaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.yaml")
+	ioutil.WriteFile(path, []byte(yamlString), 0600)
+
+	var expectedValue interface{}
+	var actualValue interface{}
+
+	//
+	//
+	//---------------------
+	// success (load)
+	roughYamlObj, err := LoadFile(path)
+	if err != nil {
+		t.Errorf("<< FAILED >>> : %v", err)
+	}
+	expectedValue = "bbb1"
+	actualValue = roughYamlObj.Get("aaa").Get("bbb").Value()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+	}
+	t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+
+	//
+	//
+	//---------------------
+	// success (save keeps permission)
+	roughYamlObj.Get("aaa").Set("bbb", "bbb2")
+	if err := roughYamlObj.SaveFile(path); err != nil {
+		t.Errorf("<< FAILED >>> : %v", err)
+	}
+	expectedValue = `aaa:
+  bbb: bbb2
+  ccc:
+  - 1
+`
+	bytes, _ := ioutil.ReadFile(path)
+	actualValue = string(bytes)
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+	info, _ := os.Stat(path)
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("<< FAILED >>> : %v", info.Mode())
+	}
+	files, _ := ioutil.ReadDir(dir)
+	if len(files) != 1 {
+		t.Errorf("<< FAILED >>> : temporary file is left (%v files)", len(files))
+	}
+
+	//
+	//
+	//---------------------
+	// success (not modified since saved)
+	roughYamlObj.Get("aaa").Set("bbb", "bbb3")
+	if err := roughYamlObj.SaveFileWithOptions(path, SaveOptions{FailIfModified: true}); err != nil {
+		t.Errorf("<< FAILED >>> : %v", err)
+	}
+
+	//
+	//
+	//---------------------
+	// error (modified on disk)
+	ioutil.WriteFile(path, []byte("aaa: changed\n"), 0600)
+	roughYamlObj.Get("aaa").Set("bbb", "bbb4")
+	if err := roughYamlObj.SaveFileWithOptions(path, SaveOptions{FailIfModified: true}); err != ErrFileModified {
+		t.Errorf("<< FAILED >>> : %v", err)
+	}
+	bytes, _ = ioutil.ReadFile(path)
+	if string(bytes) != "aaa: changed\n" {
+		t.Errorf("<< FAILED >>> : %v", string(bytes))
+	}
+
+	//
+	//
+	//---------------------
+	// error (not found)
+	_, err = LoadFile(filepath.Join(dir, "missing.yaml"))
+	if err == nil {
+		t.Errorf("<< FAILED >>>")
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package goroughyaml
+
+import (
+	"os"
+	"syscall"
+)
+
+// preserveOwner gives the file at path the owner of info, ignoring failures such as missing privileges.
+func preserveOwner(path string, info os.FileInfo) {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		os.Chown(path, int(stat.Uid), int(stat.Gid))
+	}
+}
@@ -0,0 +1,259 @@
+package goroughyaml
+
+import (
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v2"
+)
+
+// FrozenRoughYaml is an immutable version of a document or of a part of it.
+// With and Without return new versions which share the unchanged parts with the receiver,
+// so that keeping many versions costs only the edited paths.
+// The values it returns must not be modified.
+type FrozenRoughYaml struct {
+	key   interface{}
+	value interface{}
+	path  []string
+	err   error
+}
+
+var _ Node = (*FrozenRoughYaml)(nil)
+
+// Frozen returns an immutable copy of the node.
+func (o *RoughYaml) Frozen() *FrozenRoughYaml {
+	return &FrozenRoughYaml{value: freezeValue(o.GetContents()), err: o.err}
+}
+
+// freezeValue returns a deep copy of value whose mappings are yaml.MapSlice values instead of pointers.
+func freezeValue(value interface{}) interface{} {
+	normalized, err := normalizeValue(value)
+	if err != nil {
+		return cloneValue(value)
+	}
+	switch v := normalized.(type) {
+	case yaml.MapSlice:
+		mapSlice := make(yaml.MapSlice, len(v))
+		for index, item := range v {
+			mapSlice[index] = yaml.MapItem{Key: freezeValue(item.Key), Value: freezeValue(item.Value)}
+		}
+		return mapSlice
+	case []interface{}:
+		slice := make([]interface{}, len(v))
+		for index, element := range v {
+			slice[index] = freezeValue(element)
+		}
+		return slice
+	}
+	return normalized
+}
+
+// Key returns the key of the node, or nil for the root and sequence entries.
+func (f *FrozenRoughYaml) Key() interface{} {
+	return f.key
+}
+
+// Value returns the value of the node.
+func (f *FrozenRoughYaml) Value() interface{} {
+	return f.value
+}
+
+// GetContents returns the value of the node.
+func (f *FrozenRoughYaml) GetContents() interface{} {
+	return f.value
+}
+
+// ToYaml returns the node as yaml.
+func (f *FrozenRoughYaml) ToYaml() (string, error) {
+	bytes, err := yaml.Marshal(f.value)
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
+
+// Err returns the first failure in the chain of Get calls which returned the node, or nil if the node exists.
+func (f *FrozenRoughYaml) Err() error {
+	return f.err
+}
+
+// Path returns the keys from the frozen root to the node, sequence indexes as strings.
+func (f *FrozenRoughYaml) Path() []string {
+	return append([]string{}, f.path...)
+}
+
+// Get returns the child node for key, which is a mapping key or a sequence index.
+func (f *FrozenRoughYaml) Get(key string) *FrozenRoughYaml {
+	child := &FrozenRoughYaml{path: appendPath(f.path, key), err: f.err}
+	if f.err != nil {
+		return child
+	}
+	switch v := f.value.(type) {
+	case yaml.MapSlice:
+		if index := findKeyIndex(v, key); index >= 0 {
+			child.key = v[index].Key
+			child.value = v[index].Value
+			return child
+		}
+		child.err = fmt.Errorf("key '%v' not found under %v", key, describePath(f.path))
+	case []interface{}:
+		if index, ok := sequenceIndex(key); ok && index >= 0 && index < len(v) {
+			child.value = v[index]
+			return child
+		}
+		child.err = fmt.Errorf("index %v out of range (len %v) under %v", key, len(v), describePath(f.path))
+	default:
+		child.err = fmt.Errorf("key '%v' not found: %v is not a mapping or a sequence", key, describePath(f.path))
+	}
+	return child
+}
+
+// GetPath returns the node reached by calling Get with each key in turn.
+func (f *FrozenRoughYaml) GetPath(keys ...string) *FrozenRoughYaml {
+	node := f
+	for _, key := range keys {
+		node = node.Get(key)
+	}
+	return node
+}
+
+// Lookup returns the child node for key as a read-only Node.
+func (f *FrozenRoughYaml) Lookup(key string) Node {
+	return f.Get(key)
+}
+
+// With returns a new version of the node in which the value at path is value.
+// Missing mapping keys on the path are added.
+func (f *FrozenRoughYaml) With(path []string, value interface{}) (*FrozenRoughYaml, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	newValue, err := withValue(f.value, path, freezeValue(value), f.path)
+	if err != nil {
+		return nil, err
+	}
+	return &FrozenRoughYaml{key: f.key, value: newValue, path: f.path}, nil
+}
+
+// Without returns a new version of the node in which the key or sequence entry at path is removed.
+func (f *FrozenRoughYaml) Without(path []string) (*FrozenRoughYaml, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if len(path) == 0 {
+		return nil, errors.New("path is empty")
+	}
+	newValue, err := withoutValue(f.value, path, f.path)
+	if err != nil {
+		return nil, err
+	}
+	return &FrozenRoughYaml{key: f.key, value: newValue, path: f.path}, nil
+}
+
+// Thaw returns a mutable copy of the node as the root of an independent document.
+func (f *FrozenRoughYaml) Thaw() *RoughYaml {
+	var contents interface{}
+	switch v := cloneValue(f.value).(type) {
+	case yaml.MapSlice:
+		contents = &v
+	case nil:
+	default:
+		value := interface{}(v)
+		contents = &value
+	}
+	thawed := newRoughYaml(contents)
+	return &thawed
+}
+
+// withValue returns a copy of node in which the value at path is value.
+// Only the mappings and sequences on the path are copied.
+func withValue(node interface{}, path []string, value interface{}, parentPath []string) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	key := path[0]
+	childPath := appendPath(parentPath, key)
+	switch v := node.(type) {
+	case nil:
+		child, err := withValue(nil, path[1:], value, childPath)
+		if err != nil {
+			return nil, err
+		}
+		return yaml.MapSlice{yaml.MapItem{Key: key, Value: child}}, nil
+	case yaml.MapSlice:
+		index := findKeyIndex(v, key)
+		copied := make(yaml.MapSlice, len(v), len(v)+1)
+		copy(copied, v)
+		if index < 0 {
+			child, err := withValue(nil, path[1:], value, childPath)
+			if err != nil {
+				return nil, err
+			}
+			return append(copied, yaml.MapItem{Key: key, Value: child}), nil
+		}
+		child, err := withValue(v[index].Value, path[1:], value, childPath)
+		if err != nil {
+			return nil, err
+		}
+		copied[index].Value = child
+		return copied, nil
+	case []interface{}:
+		index, ok := sequenceIndex(key)
+		if !ok || index < 0 || index >= len(v) {
+			return nil, fmt.Errorf("index %v out of range (len %v) under %v", key, len(v), describePath(parentPath))
+		}
+		copied := make([]interface{}, len(v))
+		copy(copied, v)
+		child, err := withValue(v[index], path[1:], value, childPath)
+		if err != nil {
+			return nil, err
+		}
+		copied[index] = child
+		return copied, nil
+	}
+	return nil, fmt.Errorf("key '%v' not found: %v is not a mapping or a sequence", key, describePath(parentPath))
+}
+
+// withoutValue returns a copy of node in which the key or sequence entry at path is removed.
+func withoutValue(node interface{}, path []string, parentPath []string) (interface{}, error) {
+	key := path[0]
+	childPath := appendPath(parentPath, key)
+	switch v := node.(type) {
+	case yaml.MapSlice:
+		index := findKeyIndex(v, key)
+		if index < 0 {
+			return nil, fmt.Errorf("key '%v' not found under %v", key, describePath(parentPath))
+		}
+		if len(path) == 1 {
+			copied := make(yaml.MapSlice, 0, len(v)-1)
+			copied = append(copied, v[:index]...)
+			return append(copied, v[index+1:]...), nil
+		}
+		child, err := withoutValue(v[index].Value, path[1:], childPath)
+		if err != nil {
+			return nil, err
+		}
+		copied := make(yaml.MapSlice, len(v))
+		copy(copied, v)
+		copied[index].Value = child
+		return copied, nil
+	case []interface{}:
+		index, ok := sequenceIndex(key)
+		if !ok || index < 0 || index >= len(v) {
+			return nil, fmt.Errorf("index %v out of range (len %v) under %v", key, len(v), describePath(parentPath))
+		}
+		if len(path) == 1 {
+			copied := make([]interface{}, 0, len(v)-1)
+			copied = append(copied, v[:index]...)
+			return append(copied, v[index+1:]...), nil
+		}
+		child, err := withoutValue(v[index], path[1:], childPath)
+		if err != nil {
+			return nil, err
+		}
+		copied := make([]interface{}, len(v))
+		copy(copied, v)
+		copied[index] = child
+		return copied, nil
+	}
+	return nil, fmt.Errorf("key '%v' not found: %v is not a mapping or a sequence", key, describePath(parentPath))
+}
@@ -0,0 +1,119 @@
+package goroughyaml
+
+import (
+	"gopkg.in/yaml.v2"
+	"reflect"
+	"testing"
+)
+
+func TestFrozen(t *testing.T) {
+	//---------------------
+	// init
+	yamlString := `
+aaa:
+  bbb: bbb1
+  ccc:
+  - 1
+  - 2
+ddd:
+  eee: eee1
+`
+	var expectedValue interface{}
+	var actualValue interface{}
+
+	roughYamlObj := FromYaml(yamlString)
+	version1 := roughYamlObj.Frozen()
+
+	//
+	//
+	//---------------------
+	// success (editing the document keeps the frozen copy)
+	roughYamlObj.Get("aaa").Set("bbb", "changed")
+	expectedValue = "bbb1"
+	actualValue = version1.Get("aaa").Get("bbb").Value()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+	}
+	t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+
+	//
+	//
+	//---------------------
+	// success (with)
+	version2, _ := version1.With([]string{"aaa", "ccc", "1"}, 20)
+	version3, _ := version2.With([]string{"fff", "ggg"}, "ggg1")
+	expectedValue = `aaa:
+  bbb: bbb1
+  ccc:
+  - 1
+  - 20
+ddd:
+  eee: eee1
+fff:
+  ggg: ggg1
+`
+	actualValue, _ = version3.ToYaml()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+	expectedValue = 2
+	actualValue = version1.GetPath("aaa", "ccc", "1").Value()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+	}
+	t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+
+	//
+	//
+	//---------------------
+	// success (unchanged parts are shared)
+	ddd1 := version1.Get("ddd").Value().(yaml.MapSlice)
+	ddd3 := version3.Get("ddd").Value().(yaml.MapSlice)
+	if reflect.ValueOf(ddd1).Pointer() != reflect.ValueOf(ddd3).Pointer() {
+		t.Errorf("<< FAILED >>>")
+	}
+
+	//
+	//
+	//---------------------
+	// success (without)
+	version4, _ := version3.Without([]string{"aaa", "ccc", "0"})
+	version4, _ = version4.Without([]string{"ddd"})
+	expectedValue = `aaa:
+  bbb: bbb1
+  ccc:
+  - 20
+fff:
+  ggg: ggg1
+`
+	actualValue, _ = version4.ToYaml()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+
+	//
+	//
+	//---------------------
+	// success (thaw)
+	thawed := version4.Thaw()
+	thawed.Get("aaa").Set("bbb", "thawed")
+	expectedValue = "bbb1"
+	actualValue = version4.Get("aaa").Get("bbb").Value()
+	if actualValue != expectedValue || thawed.Get("aaa").Get("bbb").Value() != "thawed" {
+		t.Errorf("<< FAILED >>>")
+	}
+	t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+
+	//
+	//
+	//---------------------
+	// error (missing path)
+	if _, err := version1.Without([]string{"xxx"}); err == nil {
+		t.Errorf("<< FAILED >>>")
+	}
+	if _, err := version1.With([]string{"aaa", "bbb", "xxx"}, 1); err == nil {
+		t.Errorf("<< FAILED >>>")
+	}
+}
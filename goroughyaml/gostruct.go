@@ -0,0 +1,186 @@
+package goroughyaml
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"gopkg.in/yaml.v2"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// GoStructOptions configures GenerateGoStructs.
+type GoStructOptions struct {
+	// Package is the name in the package clause, "main" if empty.
+	Package string
+	// TypeName is the name of the type of the whole document, "Config" if empty.
+	TypeName string
+}
+
+// goInitialisms are the words which are written in upper case in Go names.
+var goInitialisms = map[string]bool{
+	"api": true, "cpu": true, "dns": true, "html": true, "http": true, "https": true, "id": true,
+	"ip": true, "json": true, "sql": true, "ssh": true, "tcp": true, "tls": true, "ttl": true,
+	"udp": true, "uid": true, "uri": true, "url": true, "uuid": true, "xml": true, "yaml": true,
+}
+
+// GenerateGoStructs returns formatted Go source which declares types with yaml tags for the example documents.
+// The types are inferred as InferSchema does. Each mapping becomes a struct type named after its parent
+// type and key, with the fields in the order of the keys, and the elements of a sequence share one type.
+// Keys missing in some examples are tagged omitempty, and values of different types become interface{}.
+func GenerateGoStructs(options GoStructOptions, docs ...*RoughYaml) ([]byte, error) {
+	for _, doc := range docs {
+		if doc.err != nil {
+			return nil, doc.err
+		}
+	}
+	if options.Package == "" {
+		options.Package = "main"
+	}
+	if options.TypeName == "" {
+		options.TypeName = "Config"
+	}
+	g := &goStructGenerator{names: map[string]bool{}}
+	root, _ := plainValue(InferSchema(docs...).root).(yaml.MapSlice)
+	g.declare(options.TypeName, root)
+	source := &bytes.Buffer{}
+	fmt.Fprintf(source, "package %s\n", options.Package)
+	for _, declaration := range g.declarations {
+		source.WriteString("\n" + declaration)
+	}
+	return format.Source(source.Bytes())
+}
+
+type goStructGenerator struct {
+	declarations []string
+	names        map[string]bool
+}
+
+// declare adds a type declaration for schema named name, or name with a number suffix if it is used, and returns the name.
+func (g *goStructGenerator) declare(name string, schema yaml.MapSlice) string {
+	name = uniqueGoName(name, g.names)
+	index := len(g.declarations)
+	g.declarations = append(g.declarations, "")
+	typ, ok := g.goStruct(name, schema)
+	if !ok {
+		typ = g.goType(name, schema)
+	}
+	g.declarations[index] = fmt.Sprintf("type %s %s\n", name, typ)
+	return name
+}
+
+// goType returns the Go type for schema, declaring the struct types it needs with names starting with name.
+// A value which is null in some examples is a pointer, unless its type can be nil.
+func (g *goStructGenerator) goType(name string, schema yaml.MapSlice) string {
+	var types []string
+	nullable := false
+	switch v := lookupKeyword(schema, "type").(type) {
+	case string:
+		types = []string{v}
+	case []interface{}:
+		for _, t := range v {
+			if t == "null" {
+				nullable = true
+			} else {
+				types = append(types, fmt.Sprint(t))
+			}
+		}
+	}
+	if len(types) != 1 {
+		return "interface{}"
+	}
+	pointer := ""
+	if nullable {
+		pointer = "*"
+	}
+	switch types[0] {
+	case "string":
+		return pointer + "string"
+	case "integer":
+		return pointer + "int"
+	case "number":
+		return pointer + "float64"
+	case "boolean":
+		return pointer + "bool"
+	case "array":
+		items, ok := schemaKeyword(schema, "items")
+		if !ok {
+			return "[]interface{}"
+		}
+		object, _ := plainValue(items).(yaml.MapSlice)
+		return "[]" + g.goType(name+"Item", object)
+	case "object":
+		if len(plainMapping(lookupKeyword(schema, "properties"))) == 0 {
+			return "map[string]interface{}"
+		}
+		return pointer + g.declare(name, schema)
+	}
+	return "interface{}"
+}
+
+// goStruct returns the struct type for the properties of schema, or false if it has none.
+func (g *goStructGenerator) goStruct(name string, schema yaml.MapSlice) (string, bool) {
+	properties := plainMapping(lookupKeyword(schema, "properties"))
+	if len(properties) == 0 {
+		return "", false
+	}
+	required := map[string]bool{}
+	for _, key := range schemaList(lookupKeyword(schema, "required")) {
+		required[fmt.Sprint(key)] = true
+	}
+	fields := map[string]bool{}
+	typ := &bytes.Buffer{}
+	typ.WriteString("struct {\n")
+	for _, property := range properties {
+		key := keyString(property.Key)
+		field := uniqueGoName(goName(key), fields)
+		object, _ := plainValue(property.Value).(yaml.MapSlice)
+		tag := `yaml:` + strconv.Quote(key)
+		if !required[key] {
+			tag = `yaml:` + strconv.Quote(key+",omitempty")
+		}
+		if strings.Contains(tag, "`") {
+			tag = strconv.Quote(tag)
+		} else {
+			tag = "`" + tag + "`"
+		}
+		fmt.Fprintf(typ, "%s %s %s\n", field, g.goType(name+field, object), tag)
+	}
+	typ.WriteString("}")
+	return typ.String(), true
+}
+
+// goName returns an exported Go name for key, such as ServerURL for "server-url".
+func goName(key string) string {
+	words := strings.FieldsFunc(key, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var name strings.Builder
+	for _, word := range words {
+		if goInitialisms[strings.ToLower(word)] {
+			name.WriteString(strings.ToUpper(word))
+			continue
+		}
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		name.WriteString(string(runes))
+	}
+	if name.Len() == 0 {
+		return "Field"
+	}
+	if first := []rune(name.String())[0]; !unicode.IsUpper(first) {
+		return "X" + name.String()
+	}
+	return name.String()
+}
+
+// uniqueGoName returns name, or name with the smallest number suffix which is not in names, and adds it to names.
+func uniqueGoName(name string, names map[string]bool) string {
+	unique := name
+	for number := 2; names[unique]; number++ {
+		unique = name + strconv.Itoa(number)
+	}
+	names[unique] = true
+	return unique
+}
@@ -0,0 +1,94 @@
+package goroughyaml
+
+import (
+	"testing"
+)
+
+func TestGenerateGoStructs(t *testing.T) {
+	//---------------------
+	// init
+	first := FromYaml(`
+name: app
+server:
+  host: localhost
+  port: 8080
+  api-url: http://localhost/api
+workers:
+- name: aaa
+  threads: 1
+- name: bbb
+  ratio: 0.5
+labels: {}
+`)
+	second := FromYaml(`
+name: app
+server:
+  host: example.com
+  port: null
+workers: []
+timeout: 30
+`)
+	var expectedValue interface{}
+	var actualValue interface{}
+
+	//
+	//
+	//---------------------
+	// success
+	source, err := GenerateGoStructs(GoStructOptions{Package: "config", TypeName: "App"}, &first, &second)
+	if err != nil {
+		t.Errorf("<< FAILED >>> : %v", err)
+	}
+	expectedValue = "package config\n" +
+		"\n" +
+		"type App struct {\n" +
+		"\tName    string                 `yaml:\"name\"`\n" +
+		"\tServer  AppServer              `yaml:\"server\"`\n" +
+		"\tWorkers []AppWorkersItem       `yaml:\"workers\"`\n" +
+		"\tLabels  map[string]interface{} `yaml:\"labels,omitempty\"`\n" +
+		"\tTimeout int                    `yaml:\"timeout,omitempty\"`\n" +
+		"}\n" +
+		"\n" +
+		"type AppServer struct {\n" +
+		"\tHost   string `yaml:\"host\"`\n" +
+		"\tPort   *int   `yaml:\"port\"`\n" +
+		"\tAPIURL string `yaml:\"api-url,omitempty\"`\n" +
+		"}\n" +
+		"\n" +
+		"type AppWorkersItem struct {\n" +
+		"\tName    string  `yaml:\"name\"`\n" +
+		"\tThreads int     `yaml:\"threads,omitempty\"`\n" +
+		"\tRatio   float64 `yaml:\"ratio,omitempty\"`\n" +
+		"}\n"
+	actualValue = string(source)
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+}
+
+func TestGoName(t *testing.T) {
+	//---------------------
+	// init
+	var expectedValue interface{}
+	var actualValue interface{}
+
+	//
+	//
+	//---------------------
+	// success
+	for key, name := range map[string]string{
+		"server-url": "ServerURL",
+		"max_size":   "MaxSize",
+		"maxSize":    "MaxSize",
+		"1st":        "X1st",
+		"--":         "Field",
+	} {
+		expectedValue = name
+		actualValue = goName(key)
+		if actualValue != expectedValue {
+			t.Errorf("<< FAILED >>>")
+			t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+		}
+	}
+}
@@ -0,0 +1,441 @@
+package goroughyaml
+
+import (
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"sort"
+	"sync"
+)
+
+var (
+	// ErrTransactionInProgress is returned by Begin, Undo and Redo while a transaction is in progress.
+	ErrTransactionInProgress = errors.New("goroughyaml: transaction already in progress")
+	// ErrNoTransaction is returned by Commit and Rollback when no transaction is in progress.
+	ErrNoTransaction = errors.New("goroughyaml: no transaction in progress")
+	// ErrNothingToUndo is returned by Undo when the undo history is empty.
+	ErrNothingToUndo = errors.New("goroughyaml: nothing to undo")
+	// ErrNothingToRedo is returned by Redo when there is no undone edit.
+	ErrNothingToRedo = errors.New("goroughyaml: nothing to redo")
+	// ErrDetachedNode is returned for a node which does not belong to a document, such as a zero RoughYaml.
+	ErrDetachedNode = errors.New("goroughyaml: node does not belong to a document")
+)
+
+// document holds the state shared by all the nodes of a document.
+type document struct {
+	root      *yaml.MapItem
+	history   history
+	observers []*observer
+	audit     auditLog
+	sources   map[string]string
+	text      string
+	lines     map[string]int
+	linesOnce sync.Once
+}
+
+// history records the edits of a document for transactions and undo/redo.
+type history struct {
+	limit         int
+	undo          [][]editRecord
+	redo          [][]editRecord
+	transaction   []editRecord
+	inTransaction bool
+}
+
+// editRecord is an edit of the node at keys, the keys from the root of the document in their own types.
+type editRecord struct {
+	keys   []interface{}
+	before editState
+	after  editState
+}
+
+// editState is the state of an edited node: the entries of the keys which the edit changes,
+// or with whole, a copy of its contents for edits which change the node as a whole, such as SortKeys.
+type editState struct {
+	whole    bool
+	contents interface{}
+	isNil    bool
+	entries  []entryState
+}
+
+// entryState is a key of a mapping or an index of a sequence, with its position and a copy of its value if it exists.
+type entryState struct {
+	key    interface{}
+	exists bool
+	index  int
+	value  interface{}
+}
+
+func (o *RoughYaml) isRoot() bool {
+	return o.doc != nil && o.currentItem == o.doc.root
+}
+
+// recordEdit runs fn, which changes the entries for keys in the contents of o, records the change in the history
+// of the document and reports change to the hooks of the document.
+// The history keeps copies of the values of the entries, or of the whole contents if keys is nil,
+// as for OperationReorder, or if fn changes the contents to another kind of node.
+// For OperationReorder, the values of change are filled in with the mapping before and after fn,
+// copied deeply with deep because fn also changes nested mappings in place.
+func (o *RoughYaml) recordEdit(deep bool, change Change, keys []interface{}, fn func() error) error {
+	if o.doc == nil {
+		return fn()
+	}
+	recording := o.doc.history.recording()
+	whole := keys == nil || !o.hasEntries(keys)
+	var before editState
+	if recording {
+		before = o.editState(whole, keys)
+	}
+	var oldValue interface{}
+	if change.Operation == OperationReorder {
+		oldValue = mappingValue(snapshotContents(o.GetContents(), deep))
+	}
+	if err := fn(); err != nil {
+		return err
+	}
+	if recording {
+		keysCopy := append([]interface{}{}, o.keys...)
+		o.doc.history.record(editRecord{keys: keysCopy, before: before, after: o.editState(whole, keys)})
+	}
+	if change.Operation == OperationReorder {
+		change.OldValue = oldValue
+		change.NewValue = mappingValue(snapshotContents(o.GetContents(), false))
+	}
+	o.doc.notify(change)
+	return nil
+}
+
+// hasEntries reports whether the contents of o can hold entries for keys, so that an edit of them does not
+// change the kind of the node: a mapping, nothing yet, or a sequence with all of keys as its indexes.
+func (o *RoughYaml) hasEntries(keys []interface{}) bool {
+	switch v := o.GetContents().(type) {
+	case nil, *yaml.MapSlice:
+		return true
+	case *interface{}:
+		elements, ok := (*v).([]interface{})
+		for _, key := range keys {
+			if index, isIndex := sequenceIndex(key); !ok || !isIndex || index < 0 || index >= len(elements) {
+				return false
+			}
+		}
+		return ok
+	}
+	return false
+}
+
+// editState returns the current state of the entries for keys in the contents of o, or of the whole contents.
+func (o *RoughYaml) editState(whole bool, keys []interface{}) editState {
+	contents := o.GetContents()
+	if whole {
+		return editState{whole: true, contents: snapshotContents(contents, true)}
+	}
+	state := editState{isNil: contents == nil}
+	for _, key := range keys {
+		entry := entryState{key: key}
+		switch v := contents.(type) {
+		case *yaml.MapSlice:
+			if index := findKeyIndex(*v, key); index >= 0 {
+				entry = entryState{key: (*v)[index].Key, exists: true, index: index, value: cloneValue((*v)[index].Value)}
+			}
+		case *interface{}:
+			elements, _ := (*v).([]interface{})
+			if index, ok := sequenceIndex(key); ok && index >= 0 && index < len(elements) {
+				entry = entryState{key: index, exists: true, index: index, value: cloneValue(elements[index])}
+			}
+		}
+		state.entries = append(state.entries, entry)
+	}
+	return state
+}
+
+func mappingValue(contents interface{}) interface{} {
+	if mapSlice, ok := contents.(*yaml.MapSlice); ok {
+		return *mapSlice
+	}
+	return contents
+}
+
+// snapshotContents copies contents, which is a *yaml.MapSlice, an *interface{} or nil as GetContents returns it.
+// Without deep, only the outermost mapping or sequence is copied.
+func snapshotContents(contents interface{}, deep bool) interface{} {
+	switch v := contents.(type) {
+	case *yaml.MapSlice:
+		var mapSlice yaml.MapSlice
+		if deep {
+			mapSlice = cloneMapSlice(*v)
+		} else {
+			mapSlice = append(yaml.MapSlice{}, *v...)
+		}
+		return &mapSlice
+	case *interface{}:
+		value := *v
+		if deep {
+			value = cloneValue(value)
+		} else if slice, ok := value.([]interface{}); ok {
+			value = append([]interface{}{}, slice...)
+		}
+		return &value
+	}
+	return contents
+}
+
+func (h *history) recording() bool {
+	return h.inTransaction || h.limit != 0
+}
+
+func (h *history) record(record editRecord) {
+	if h.inTransaction {
+		h.transaction = append(h.transaction, record)
+		return
+	}
+	h.push([]editRecord{record})
+}
+
+func (h *history) push(records []editRecord) {
+	if h.limit == 0 || len(records) == 0 {
+		return
+	}
+	h.undo = append(h.undo, records)
+	if h.limit > 0 && len(h.undo) > h.limit {
+		h.undo = h.undo[len(h.undo)-h.limit:]
+	}
+	h.redo = nil
+}
+
+// rootNode returns the root node of the document.
+func (d *document) rootNode() *RoughYaml {
+	root := createRoughYaml(d.root.Value, d.root)
+	root.doc = d
+	return root
+}
+
+// restore brings the node at keys back to state. It returns an error if the node is no longer in the document.
+func (d *document) restore(keys []interface{}, state editState) error {
+	node := d.rootNode()
+	for _, key := range keys {
+		node = node.GetKey(key)
+	}
+	if node.currentItem == nil {
+		return fmt.Errorf("cannot restore %v: it is no longer in the document", describePath(node.path))
+	}
+	if state.whole {
+		value := snapshotContents(state.contents, true)
+		if pointer, ok := value.(*interface{}); ok && !node.isRoot() {
+			value = *pointer
+		}
+		oldValue := node.Value()
+		setContentsValue(node, value)
+		d.notify(Change{Operation: OperationReplace, Path: node.Path(), OldValue: oldValue, NewValue: value})
+		return nil
+	}
+	var existing []entryState
+	for _, entry := range state.entries {
+		if entry.exists {
+			existing = append(existing, entry)
+		} else {
+			d.removeEntry(node, entry.key)
+		}
+	}
+	// entries are inserted from the lowest index, so that each one finds the entries before it in place
+	sort.SliceStable(existing, func(i, j int) bool {
+		return existing[i].index < existing[j].index
+	})
+	for _, entry := range existing {
+		if err := d.restoreEntry(node, entry); err != nil {
+			return err
+		}
+	}
+	if mapSlice, ok := node.GetContents().(*yaml.MapSlice); ok && state.isNil && len(*mapSlice) == 0 {
+		setContentsValue(node, nil)
+	}
+	return nil
+}
+
+// removeEntry removes key from the mapping of node if it exists.
+func (d *document) removeEntry(node *RoughYaml, key interface{}) {
+	mapSlice, ok := node.GetContents().(*yaml.MapSlice)
+	if !ok {
+		return
+	}
+	index := findKeyIndex(*mapSlice, key)
+	if index < 0 {
+		return
+	}
+	oldValue := (*mapSlice)[index].Value
+	newMapSlice := append(append(yaml.MapSlice{}, (*mapSlice)[:index]...), (*mapSlice)[index+1:]...)
+	setContentsValue(node, &newMapSlice)
+	d.notify(Change{Operation: OperationDelete, Path: appendPath(node.path, keyString(key)), OldValue: oldValue})
+}
+
+// restoreEntry sets the value of the entry in node, adding it at its index if it does not exist.
+func (d *document) restoreEntry(node *RoughYaml, entry entryState) error {
+	value := cloneValue(entry.value)
+	path := appendPath(node.path, keyString(entry.key))
+	if child := node.GetKey(entry.key); child.currentItem != nil {
+		oldValue := child.Value()
+		setContentsValue(child, value)
+		d.notify(Change{Operation: OperationReplace, Path: path, OldValue: oldValue, NewValue: value})
+		return nil
+	}
+	mapSlice := &yaml.MapSlice{}
+	if contents := node.GetContents(); contents != nil {
+		var ok bool
+		if mapSlice, ok = contents.(*yaml.MapSlice); !ok {
+			return fmt.Errorf("cannot restore %v: %v is not a mapping", describePath(path), describePath(node.path))
+		}
+	}
+	index := entry.index
+	if index > len(*mapSlice) {
+		index = len(*mapSlice)
+	}
+	newMapSlice := make(yaml.MapSlice, 0, len(*mapSlice)+1)
+	newMapSlice = append(newMapSlice, (*mapSlice)[:index]...)
+	newMapSlice = append(newMapSlice, yaml.MapItem{Key: entry.key, Value: value})
+	newMapSlice = append(newMapSlice, (*mapSlice)[index:]...)
+	setContentsValue(node, &newMapSlice)
+	d.notify(Change{Operation: OperationAdd, Path: path, NewValue: value})
+	return nil
+}
+
+// undo brings the nodes of records back to their states before the edits, in reverse order.
+// It returns the first failure, after restoring what it can.
+func (d *document) undo(records []editRecord) error {
+	var first error
+	for index := len(records) - 1; index >= 0; index-- {
+		if err := d.restore(records[index].keys, records[index].before); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// redo brings the nodes of records to their states after the edits, in order.
+func (d *document) redo(records []editRecord) error {
+	var first error
+	for _, record := range records {
+		if err := d.restore(record.keys, record.after); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// Begin starts a transaction on the document of the node.
+// The edits until Commit are undone at once, and Rollback undoes them.
+func (o *RoughYaml) Begin() error {
+	if o.doc == nil {
+		return ErrDetachedNode
+	}
+	h := &o.doc.history
+	if h.inTransaction {
+		return ErrTransactionInProgress
+	}
+	h.inTransaction = true
+	h.transaction = nil
+	return nil
+}
+
+// Commit ends the transaction, keeping its edits.
+func (o *RoughYaml) Commit() error {
+	if o.doc == nil {
+		return ErrDetachedNode
+	}
+	h := &o.doc.history
+	if !h.inTransaction {
+		return ErrNoTransaction
+	}
+	h.inTransaction = false
+	h.push(h.transaction)
+	h.transaction = nil
+	return nil
+}
+
+// Rollback ends the transaction, undoing its edits.
+// It returns an error if a node which an edit changed is no longer in the document, such as a node
+// kept from before its parent was renamed, after undoing the other edits.
+func (o *RoughYaml) Rollback() error {
+	if o.doc == nil {
+		return ErrDetachedNode
+	}
+	h := &o.doc.history
+	if !h.inTransaction {
+		return ErrNoTransaction
+	}
+	err := o.doc.undo(h.transaction)
+	h.inTransaction = false
+	h.transaction = nil
+	return err
+}
+
+// SetUndoLimit enables the undo history of the document of the node, keeping the last limit edits or transactions.
+// A negative limit keeps all of them, and 0 disables the history, which is the default.
+// It does nothing for a node which does not belong to a document.
+func (o *RoughYaml) SetUndoLimit(limit int) {
+	if o.doc == nil {
+		return
+	}
+	h := &o.doc.history
+	h.limit = limit
+	if limit == 0 {
+		h.undo = nil
+		h.redo = nil
+	} else if limit > 0 && len(h.undo) > limit {
+		h.undo = h.undo[len(h.undo)-limit:]
+	}
+}
+
+// CanUndo reports whether Undo has an edit to undo.
+func (o *RoughYaml) CanUndo() bool {
+	return o.doc != nil && !o.doc.history.inTransaction && len(o.doc.history.undo) > 0
+}
+
+// CanRedo reports whether Redo has an edit to redo.
+func (o *RoughYaml) CanRedo() bool {
+	return o.doc != nil && !o.doc.history.inTransaction && len(o.doc.history.redo) > 0
+}
+
+// Undo undoes the last edit or committed transaction of the document.
+// Nodes returned by Get before Undo may no longer be part of the document.
+// If a node which an edit changed is no longer in the document, Undo undoes what it can,
+// returns an error and drops the edit from the history.
+func (o *RoughYaml) Undo() error {
+	if o.doc == nil {
+		return ErrDetachedNode
+	}
+	h := &o.doc.history
+	if h.inTransaction {
+		return ErrTransactionInProgress
+	}
+	if len(h.undo) == 0 {
+		return ErrNothingToUndo
+	}
+	records := h.undo[len(h.undo)-1]
+	h.undo = h.undo[:len(h.undo)-1]
+	if err := o.doc.undo(records); err != nil {
+		return err
+	}
+	h.redo = append(h.redo, records)
+	return nil
+}
+
+// Redo redoes the last undone edit or transaction of the document.
+// Like Undo, it returns an error and drops the edit if a node which it changed is no longer in the document.
+func (o *RoughYaml) Redo() error {
+	if o.doc == nil {
+		return ErrDetachedNode
+	}
+	h := &o.doc.history
+	if h.inTransaction {
+		return ErrTransactionInProgress
+	}
+	if len(h.redo) == 0 {
+		return ErrNothingToRedo
+	}
+	records := h.redo[len(h.redo)-1]
+	h.redo = h.redo[:len(h.redo)-1]
+	if err := o.doc.redo(records); err != nil {
+		return err
+	}
+	h.undo = append(h.undo, records)
+	return nil
+}
@@ -0,0 +1,311 @@
+package goroughyaml
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTransaction(t *testing.T) {
+	//---------------------
+	// init
+	yamlString := `
+aaa:
+  bbb: bbb1
+  ccc:
+  - ddd: ddd1
+`
+	var expectedValue interface{}
+	var actualValue interface{}
+
+	roughYamlObj := FromYaml(yamlString)
+	expectedValue, _ = roughYamlObj.ToYaml()
+
+	//
+	//
+	//---------------------
+	// success (rollback)
+	roughYamlObj.Begin()
+	roughYamlObj.Get("aaa").Set("bbb", "changed")
+	roughYamlObj.Get("aaa").SetForce("eee", "added")
+	roughYamlObj.Get("aaa").Get("ccc").Get("0").SetForce("fff", "added")
+	roughYamlObj.Get("aaa").RenameKey("bbb", "zzz")
+	roughYamlObj.Delete("aaa")
+	roughYamlObj.SetForce("ggg", 1)
+	if err := roughYamlObj.Rollback(); err != nil {
+		t.Errorf("<< FAILED >>> : %v", err)
+	}
+	actualValue, _ = roughYamlObj.ToYaml()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+
+	//
+	//
+	//---------------------
+	// success (commit)
+	roughYamlObj.Begin()
+	roughYamlObj.Get("aaa").Get("ccc").Get("0").SetForce("fff", "added")
+	roughYamlObj.Get("aaa").MoveToIndex("ccc", 0)
+	roughYamlObj.Commit()
+	expectedValue = `aaa:
+  ccc:
+  - ddd: ddd1
+    fff: added
+  bbb: bbb1
+`
+	actualValue, _ = roughYamlObj.ToYaml()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+
+	//
+	//
+	//---------------------
+	// error (state)
+	if roughYamlObj.Commit() != ErrNoTransaction || roughYamlObj.Rollback() != ErrNoTransaction {
+		t.Errorf("<< FAILED >>>")
+	}
+	roughYamlObj.Begin()
+	if roughYamlObj.Begin() != ErrTransactionInProgress || roughYamlObj.Undo() != ErrTransactionInProgress {
+		t.Errorf("<< FAILED >>>")
+	}
+	roughYamlObj.Commit()
+}
+
+func TestTransactionNestedEdits(t *testing.T) {
+	//---------------------
+	// init
+	yamlString := `
+a:
+  b: 1
+  c: [1, 2]
+  m:
+    x: 1
+    w: 2
+`
+	var expectedValue interface{}
+	var actualValue interface{}
+
+	roughYamlObj := FromYaml(yamlString)
+	expectedValue, _ = roughYamlObj.ToYaml()
+
+	//
+	//
+	//---------------------
+	// success (rollback after editing a parent and then its children in place)
+	roughYamlObj.Begin()
+	roughYamlObj.Get("a").Set("b", 2)
+	roughYamlObj.Get("a").Get("m").MoveToIndex("w", 0)
+	roughYamlObj.Get("a").Get("m").RenameKey("x", "y")
+	roughYamlObj.Get("a").Get("c").Set("0", 9)
+	roughYamlObj.Get("a").Get("m").SortKeys(false, nil)
+	if err := roughYamlObj.Rollback(); err != nil {
+		t.Errorf("<< FAILED >>> : %v", err)
+	}
+	actualValue, _ = roughYamlObj.ToYaml()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+
+	//
+	//
+	//---------------------
+	// success (undo and redo)
+	roughYamlObj.SetUndoLimit(-1)
+	roughYamlObj.Get("a").Set("b", 2)
+	roughYamlObj.Get("a").Get("c").Set("0", 9)
+	roughYamlObj.Get("a").Get("m").RenameKey("x", "y")
+	changed, _ := roughYamlObj.ToYaml()
+	for count := 0; count < 3; count++ {
+		if err := roughYamlObj.Undo(); err != nil {
+			t.Errorf("<< FAILED >>> : %v", err)
+		}
+	}
+	actualValue, _ = roughYamlObj.ToYaml()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+	for count := 0; count < 3; count++ {
+		if err := roughYamlObj.Redo(); err != nil {
+			t.Errorf("<< FAILED >>> : %v", err)
+		}
+	}
+	expectedValue = changed
+	actualValue, _ = roughYamlObj.ToYaml()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+}
+
+func TestUndoRedo(t *testing.T) {
+	//---------------------
+	// init
+	yamlString := `
+aaa:
+  bbb: bbb1
+  ccc:
+    ddd: ddd1
+`
+	var expectedValue interface{}
+	var actualValue interface{}
+
+	roughYamlObj := FromYaml(yamlString)
+	roughYamlObj.SetUndoLimit(-1)
+	version0, _ := roughYamlObj.ToYaml()
+
+	//
+	//
+	//---------------------
+	// success (undo single edits and a transaction)
+	roughYamlObj.Get("aaa").Set("bbb", "bbb2")
+	version1, _ := roughYamlObj.ToYaml()
+	roughYamlObj.Begin()
+	roughYamlObj.Get("aaa").Get("ccc").SetForce("eee", "eee1")
+	roughYamlObj.Get("aaa").SortKeys(true, func(a interface{}, b interface{}) bool {
+		return a.(string) > b.(string)
+	})
+	roughYamlObj.Commit()
+	version2, _ := roughYamlObj.ToYaml()
+
+	roughYamlObj.Undo()
+	actualValue, _ = roughYamlObj.ToYaml()
+	if actualValue != version1 {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, version1)
+	}
+	roughYamlObj.Undo()
+	actualValue, _ = roughYamlObj.ToYaml()
+	if actualValue != version0 {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, version0)
+	}
+	if roughYamlObj.Undo() != ErrNothingToUndo {
+		t.Errorf("<< FAILED >>>")
+	}
+
+	//
+	//
+	//---------------------
+	// success (redo)
+	roughYamlObj.Redo()
+	roughYamlObj.Redo()
+	actualValue, _ = roughYamlObj.ToYaml()
+	if actualValue != version2 {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, version2)
+	}
+	if roughYamlObj.Redo() != ErrNothingToRedo {
+		t.Errorf("<< FAILED >>>")
+	}
+
+	//
+	//
+	//---------------------
+	// success (a new edit clears redo)
+	roughYamlObj.Undo()
+	roughYamlObj.Get("aaa").Delete("bbb")
+	expectedValue = `aaa:
+  ccc:
+    ddd: ddd1
+`
+	actualValue, _ = roughYamlObj.ToYaml()
+	if actualValue != expectedValue || roughYamlObj.CanRedo() {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+
+	//
+	//
+	//---------------------
+	// success (limit)
+	roughYamlObj.SetUndoLimit(1)
+	if roughYamlObj.Undo() != nil || roughYamlObj.CanUndo() {
+		t.Errorf("<< FAILED >>>")
+	}
+}
+
+func TestHistoryDetachedNode(t *testing.T) {
+	//---------------------
+	// init
+	var expectedValue interface{}
+	var actualValue interface{}
+
+	//
+	//
+	//---------------------
+	// error
+	var roughYamlObj RoughYaml
+	roughYamlObj.SetUndoLimit(-1)
+	expectedValue = []error{ErrDetachedNode, ErrDetachedNode, ErrDetachedNode, ErrDetachedNode, ErrDetachedNode}
+	actualValue = []error{roughYamlObj.Begin(), roughYamlObj.Commit(), roughYamlObj.Rollback(), roughYamlObj.Undo(), roughYamlObj.Redo()}
+	if !reflect.DeepEqual(actualValue, expectedValue) || roughYamlObj.CanUndo() || roughYamlObj.CanRedo() {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+}
+
+func TestUndoKeys(t *testing.T) {
+	//---------------------
+	// init
+	yamlString := `
+codes:
+  "200":
+    message: text
+  200:
+    message: number
+a:
+  b: 1
+`
+	var expectedValue interface{}
+	var actualValue interface{}
+
+	roughYamlObj := FromYaml(yamlString)
+	roughYamlObj.SetUndoLimit(-1)
+	expectedValue, _ = roughYamlObj.ToYaml()
+
+	//
+	//
+	//---------------------
+	// success (keys of other types than string)
+	roughYamlObj.Get("codes").GetKey(200).Set("message", "changed")
+	record := roughYamlObj.doc.history.undo[0][0]
+	if record.before.whole || len(record.before.entries) != 1 || !reflect.DeepEqual(record.keys, []interface{}{"codes", 200}) {
+		t.Errorf("<< FAILED >>> : %+v", record)
+	}
+	if err := roughYamlObj.Undo(); err != nil {
+		t.Errorf("<< FAILED >>> : %v", err)
+	}
+	actualValue, _ = roughYamlObj.ToYaml()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+
+	//
+	//
+	//---------------------
+	// error (the node is no longer in the document)
+	yamlBefore, _ := roughYamlObj.ToYaml()
+	held := roughYamlObj.Get("a")
+	roughYamlObj.RenameKey("a", "z")
+	held.Set("b", 2)
+	expectedValue = "cannot restore 'a': it is no longer in the document"
+	if err := roughYamlObj.Undo(); err == nil || err.Error() != expectedValue {
+		t.Errorf("<< FAILED >>> : %v", err)
+	}
+	if err := roughYamlObj.Undo(); err != nil {
+		t.Errorf("<< FAILED >>> : %v", err)
+	}
+	// undoing the rename restores the value of the key as it was before the rename
+	expectedValue = yamlBefore
+	actualValue, _ = roughYamlObj.ToYaml()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+}
@@ -0,0 +1,146 @@
+package goroughyaml
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// IncludeOptions configures LoadFileWithIncludes.
+type IncludeOptions struct {
+	// Key is the mapping key which names the files to include. "" means "$include".
+	Key string
+}
+
+// LoadFileWithIncludes reads the yaml file at path and resolves its includes.
+//
+// A mapping which has the include key, such as
+//
+//	database:
+//	  $include: database.yaml
+//	  port: 5433
+//
+// is merged with the mappings of the named files, also in sequences, a file name or a list of file names
+// relative to the including file. The keys of the mapping itself override the included ones,
+// and mappings are merged key by key in the order of Loader. Included files can include other files;
+// an include cycle is an error. Source returns the file which each value comes from.
+// Includes are marked by a key rather than an !include tag because yaml.v2 does not report custom tags.
+func LoadFileWithIncludes(path string, options IncludeOptions) (RoughYaml, error) {
+	if options.Key == "" {
+		options.Key = "$include"
+	}
+	mapSlice, sources, err := includeFile(path, nil, nil, options)
+	if err != nil {
+		return newRoughYaml(&yaml.MapSlice{}), err
+	}
+	loaded := newRoughYaml(&mapSlice)
+	loaded.doc.sources = sources
+	return loaded, nil
+}
+
+// includeFile reads the file at path and resolves its includes, placing its contents at base in the document.
+// stack is the list of the including files.
+func includeFile(path string, base []string, stack []string, options IncludeOptions) (yaml.MapSlice, map[string]string, error) {
+	for index, including := range stack {
+		if absPath(including) == absPath(path) {
+			return nil, nil, fmt.Errorf("include cycle: %v", strings.Join(append(append([]string{}, stack[index:]...), path), " -> "))
+		}
+	}
+	bytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	mapSlice := yaml.MapSlice{}
+	if err := yaml.Unmarshal(bytes, &mapSlice); err != nil {
+		return nil, nil, fmt.Errorf("%v: %v", path, err)
+	}
+	return resolveIncludes(mapSlice, base, append(append([]string{}, stack...), path), options)
+}
+
+// resolveIncludes resolves the includes of mapSlice at path in the document, which is read from the last file of stack.
+func resolveIncludes(mapSlice yaml.MapSlice, path []string, stack []string, options IncludeOptions) (yaml.MapSlice, map[string]string, error) {
+	file := stack[len(stack)-1]
+	result := &yaml.MapSlice{}
+	sources := map[string]string{}
+	own := yaml.MapSlice{}
+	ownSources := map[string]string{}
+	for _, item := range mapSlice {
+		if keyString(item.Key) != options.Key {
+			value := item.Value
+			childPath := appendPath(path, keyString(item.Key))
+			recordSource(ownSources, childPath, file)
+			value, err := resolveValueIncludes(value, childPath, stack, options, ownSources)
+			if err != nil {
+				return nil, nil, err
+			}
+			own = append(own, yaml.MapItem{Key: item.Key, Value: value})
+			mergeMapping(result, path, yaml.MapSlice{own[len(own)-1]}, file, ownSources, sources)
+			continue
+		}
+		names, err := includeNames(item.Value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%v: %v under %v", file, err, describePath(path))
+		}
+		for _, name := range names {
+			if !filepath.IsAbs(name) {
+				name = filepath.Join(filepath.Dir(file), name)
+			}
+			included, includedSources, err := includeFile(name, path, stack, options)
+			if err != nil {
+				return nil, nil, err
+			}
+			mergeMapping(result, path, included, name, includedSources, sources)
+		}
+	}
+	// the keys of the mapping itself override the included ones wherever the include is placed
+	mergeMapping(result, path, own, file, ownSources, sources)
+	return *result, sources, nil
+}
+
+// resolveValueIncludes resolves the includes of the mappings in value at path, which can be nested in sequences,
+// and adds the sources of the included values to sources.
+func resolveValueIncludes(value interface{}, path []string, stack []string, options IncludeOptions, sources map[string]string) (interface{}, error) {
+	switch v := plainValue(value).(type) {
+	case yaml.MapSlice:
+		resolved, childSources, err := resolveIncludes(v, path, stack, options)
+		if err != nil {
+			return nil, err
+		}
+		for key, source := range childSources {
+			sources[key] = source
+		}
+		return resolved, nil
+	case []interface{}:
+		elements := make([]interface{}, len(v))
+		for index, element := range v {
+			resolved, err := resolveValueIncludes(element, appendPath(path, strconv.Itoa(index)), stack, options, sources)
+			if err != nil {
+				return nil, err
+			}
+			elements[index] = resolved
+		}
+		return elements, nil
+	}
+	return value, nil
+}
+
+func includeNames(value interface{}) ([]string, error) {
+	switch v := value.(type) {
+	case string:
+		return []string{v}, nil
+	case []interface{}:
+		names := make([]string, len(v))
+		for index, element := range v {
+			name, ok := element.(string)
+			if !ok {
+				return nil, fmt.Errorf("include %v is not a file name", element)
+			}
+			names[index] = name
+		}
+		return names, nil
+	}
+	return nil, fmt.Errorf("include %v is not a file name or a list of file names", value)
+}
@@ -0,0 +1,148 @@
+package goroughyaml
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadFileWithIncludes(t *testing.T) {
+	//---------------------
+	// init
+	dir, err := ioutil.TempDir("", "goroughyaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	os.Mkdir(filepath.Join(dir, "conf.d"), 0700)
+	files := map[string]string{
+		"config.yaml": `
+name: app
+database:
+  port: 5433
+  $include: conf.d/database.yaml
+$include: [conf.d/common.yaml]
+`,
+		"conf.d/database.yaml": `
+host: localhost
+port: 5432
+pool:
+  $include: pool.yaml
+`,
+		"conf.d/pool.yaml": `
+size: 10
+`,
+		"conf.d/common.yaml": `
+name: common
+log: info
+`,
+		"servers.yaml": `
+servers:
+- name: a
+  $include: conf.d/server.yaml
+- [name: b]
+`,
+		"conf.d/server.yaml": `
+port: 80
+`,
+		"cycle1.yaml": `
+$include: cycle2.yaml
+`,
+		"cycle2.yaml": `
+aaa:
+  $include: cycle1.yaml
+`,
+	}
+	for name, content := range files {
+		ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600)
+	}
+	var expectedValue interface{}
+	var actualValue interface{}
+
+	//
+	//
+	//---------------------
+	// success (own keys override included ones and keep their order)
+	roughYamlObj, err := LoadFileWithIncludes(filepath.Join(dir, "config.yaml"), IncludeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedValue = `name: app
+database:
+  port: 5433
+  host: localhost
+  pool:
+    size: 10
+log: info
+`
+	actualValue, _ = roughYamlObj.ToYaml()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+
+	//
+	//
+	//---------------------
+	// success (sources)
+	for _, testCase := range []struct {
+		path []string
+		file string
+	}{
+		{[]string{"name"}, "config.yaml"},
+		{[]string{"database", "port"}, "config.yaml"},
+		{[]string{"database", "host"}, "conf.d/database.yaml"},
+		{[]string{"database", "pool", "size"}, "conf.d/pool.yaml"},
+		{[]string{"log"}, "conf.d/common.yaml"},
+	} {
+		expectedValue = filepath.Join(dir, testCase.file)
+		actualValue = roughYamlObj.GetPath(testCase.path...).Source()
+		if actualValue != expectedValue {
+			t.Errorf("<< FAILED >>>")
+		}
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+
+	//
+	//
+	//---------------------
+	// success (mappings in sequences)
+	roughYamlObj, err = LoadFileWithIncludes(filepath.Join(dir, "servers.yaml"), IncludeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedValue = `servers:
+- name: a
+  port: 80
+- - name: b
+`
+	actualValue, _ = roughYamlObj.ToYaml()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+	expectedValue = filepath.Join(dir, "conf.d/server.yaml")
+	actualValue = roughYamlObj.GetPath("servers", "0", "port").Source()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+	expectedValue = filepath.Join(dir, "servers.yaml")
+	actualValue = roughYamlObj.GetPath("servers", "0", "name").Source()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+
+	//
+	//
+	//---------------------
+	// error (cycle)
+	_, err = LoadFileWithIncludes(filepath.Join(dir, "cycle1.yaml"), IncludeOptions{})
+	if err == nil || !strings.Contains(err.Error(), "include cycle") {
+		t.Errorf("<< FAILED >>> : %v", err)
+	}
+	t.Logf("err:%v\n", err)
+}
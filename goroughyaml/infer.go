@@ -0,0 +1,141 @@
+package goroughyaml
+
+import (
+	"gopkg.in/yaml.v2"
+	"regexp"
+)
+
+// maxInferredEnum is the largest number of distinct strings which InferSchema turns into an enum.
+const maxInferredEnum = 5
+
+// InferSchema returns a JSON Schema (draft 2020-12) which the example documents satisfy.
+// It infers the types of values, the properties of mappings in the order of their keys,
+// the keys present in every example as required, and the items of sequences.
+// Strings with at most 5 distinct values, at least one of which is repeated, become an enum.
+func InferSchema(docs ...*RoughYaml) *Schema {
+	var samples []interface{}
+	for _, doc := range docs {
+		samples = append(samples, plainValue(doc.GetContents()))
+	}
+	root := yaml.MapSlice{{Key: "$schema", Value: "https://json-schema.org/draft/2020-12/schema"}}
+	root = append(root, inferSchema(samples)...)
+	return &Schema{root: root, patterns: map[string]*regexp.Regexp{}}
+}
+
+func inferSchema(samples []interface{}) yaml.MapSlice {
+	schema := yaml.MapSlice{}
+	var types []interface{}
+	var objects []yaml.MapSlice
+	var elements []interface{}
+	var texts []string
+	for _, sample := range samples {
+		sample = plainValue(sample)
+		name := schemaType(sample)
+		switch v := sample.(type) {
+		case yaml.MapSlice:
+			objects = append(objects, v)
+		case []interface{}:
+			elements = append(elements, v...)
+		case string:
+			texts = append(texts, v)
+		}
+		if isFloat(sample) {
+			name = "number"
+		}
+		types = addType(types, name)
+	}
+	if len(types) == 1 {
+		schema = append(schema, yaml.MapItem{Key: "type", Value: types[0]})
+	} else if len(types) > 1 {
+		schema = append(schema, yaml.MapItem{Key: "type", Value: types})
+	}
+	if enum := inferEnum(texts); len(types) == 1 && enum != nil {
+		schema = append(schema, yaml.MapItem{Key: "enum", Value: enum})
+	}
+	if len(objects) > 0 {
+		schema = append(schema, inferObject(objects)...)
+	}
+	if len(elements) > 0 {
+		items := inferSchema(elements)
+		schema = append(schema, yaml.MapItem{Key: "items", Value: items})
+	}
+	return schema
+}
+
+func inferObject(objects []yaml.MapSlice) yaml.MapSlice {
+	var keys []interface{}
+	for _, object := range objects {
+		for _, item := range object {
+			if indexOfKey(keys, item.Key) < 0 {
+				keys = append(keys, item.Key)
+			}
+		}
+	}
+	properties := yaml.MapSlice{}
+	var required []interface{}
+	for _, key := range keys {
+		var samples []interface{}
+		for _, object := range objects {
+			if index := findKeyIndex(object, key); index >= 0 && keysEqual(object[index].Key, key) {
+				samples = append(samples, object[index].Value)
+			}
+		}
+		properties = append(properties, yaml.MapItem{Key: keyString(key), Value: inferSchema(samples)})
+		if len(samples) == len(objects) {
+			required = append(required, keyString(key))
+		}
+	}
+	schema := yaml.MapSlice{{Key: "properties", Value: properties}}
+	if len(required) > 0 {
+		schema = append(schema, yaml.MapItem{Key: "required", Value: required})
+	}
+	return schema
+}
+
+// inferEnum returns the distinct values of samples if they are few and repeated, or nil.
+func inferEnum(samples []string) []interface{} {
+	var values []interface{}
+	seen := map[string]bool{}
+	for _, sample := range samples {
+		if !seen[sample] {
+			seen[sample] = true
+			values = append(values, sample)
+		}
+	}
+	if len(values) == 0 || len(values) > maxInferredEnum || len(values) == len(samples) {
+		return nil
+	}
+	return values
+}
+
+func addType(types []interface{}, name string) []interface{} {
+	for index, existing := range types {
+		switch {
+		case existing == name:
+			return types
+		case existing == "integer" && name == "number":
+			types[index] = name
+			return types
+		case existing == "number" && name == "integer":
+			return types
+		}
+	}
+	return append(types, name)
+}
+
+func isFloat(value interface{}) bool {
+	switch value.(type) {
+	case float32, float64:
+		return true
+	}
+	return false
+}
+
+func indexOfKey(keys []interface{}, key interface{}) int {
+	for index := range keys {
+		if keysEqual(keys[index], key) {
+			return index
+		}
+	}
+	return -1
+}
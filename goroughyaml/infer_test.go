@@ -0,0 +1,102 @@
+package goroughyaml
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestInferSchema(t *testing.T) {
+	//---------------------
+	// init
+	first := FromYaml(`
+name: aaa
+mode: dev
+port: 8080
+ratio: 1
+servers:
+- host: a.example.com
+  tls: true
+`)
+	second := FromYaml(`
+name: bbb
+mode: dev
+port: 9090
+ratio: 0.5
+servers:
+- host: b.example.com
+- host: c.example.com
+  tls: false
+labels: [web, api]
+`)
+	var expectedValue interface{}
+	var actualValue interface{}
+
+	//
+	//
+	//---------------------
+	// success (yaml)
+	schema := InferSchema(&first, &second)
+	expectedValue = `$schema: https://json-schema.org/draft/2020-12/schema
+type: object
+properties:
+  name:
+    type: string
+  mode:
+    type: string
+    enum:
+    - dev
+  port:
+    type: integer
+  ratio:
+    type: number
+  servers:
+    type: array
+    items:
+      type: object
+      properties:
+        host:
+          type: string
+        tls:
+          type: boolean
+      required:
+      - host
+  labels:
+    type: array
+    items:
+      type: string
+required:
+- name
+- mode
+- port
+- ratio
+- servers
+`
+	actualValue, _ = schema.ToYaml()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+
+	//
+	//
+	//---------------------
+	// success (json)
+	jsonBytes, err := schema.ToJSON()
+	if err != nil || !json.Valid(jsonBytes) {
+		t.Errorf("<< FAILED >>> : %v, %s", err, jsonBytes)
+	}
+	reloaded, err := LoadSchema(string(jsonBytes))
+	if err != nil {
+		t.Errorf("<< FAILED >>> : %v", err)
+	}
+
+	//
+	//
+	//---------------------
+	// success (examples are valid)
+	for _, doc := range []*RoughYaml{&first, &second} {
+		if err := reloaded.Validate(doc); err != nil {
+			t.Errorf("<< FAILED >>> : %v", err)
+		}
+	}
+}
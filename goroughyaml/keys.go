@@ -0,0 +1,162 @@
+package goroughyaml
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v2"
+)
+
+// RenameKey renames oldKey to newKey, keeping its position and value.
+func (o *RoughYaml) RenameKey(oldKey string, newKey string) error {
+	mapSlice, err := o.mapping()
+	if err != nil {
+		return err
+	}
+	index := findKeyIndex(*mapSlice, oldKey)
+	if index < 0 {
+		return fmt.Errorf("key '%v' not found under %v", oldKey, describePath(o.path))
+	}
+	if existing := findKeyIndex(*mapSlice, newKey); existing >= 0 && existing != index {
+		return fmt.Errorf("key '%v' already exists under %v", newKey, describePath(o.path))
+	}
+	change := Change{
+		Operation: OperationRename,
+		Path:      appendPath(o.path, newKey),
+		From:      appendPath(o.path, keyString((*mapSlice)[index].Key)),
+		OldValue:  (*mapSlice)[index].Value,
+		NewValue:  (*mapSlice)[index].Value,
+	}
+	return o.recordEdit(false, change, []interface{}{(*mapSlice)[index].Key, newKey}, func() error {
+		(*mapSlice)[index].Key = newKey
+		return nil
+	})
+}
+
+// MoveBefore moves key to the position just before beforeKey.
+func (o *RoughYaml) MoveBefore(key string, beforeKey string) error {
+	return o.moveRelative(key, beforeKey, 0)
+}
+
+// MoveAfter moves key to the position just after afterKey.
+func (o *RoughYaml) MoveAfter(key string, afterKey string) error {
+	return o.moveRelative(key, afterKey, 1)
+}
+
+// MoveToIndex moves key so that it is at index in the mapping.
+func (o *RoughYaml) MoveToIndex(key string, index int) error {
+	mapSlice, err := o.mapping()
+	if err != nil {
+		return err
+	}
+	from := findKeyIndex(*mapSlice, key)
+	if from < 0 {
+		return fmt.Errorf("key '%v' not found under %v", key, describePath(o.path))
+	}
+	if index < 0 || index >= len(*mapSlice) {
+		return fmt.Errorf("index %v out of range (len %v) under %v", index, len(*mapSlice), describePath(o.path))
+	}
+	return o.recordEdit(false, Change{Operation: OperationReorder, Path: o.Path()}, nil, func() error {
+		moveItem(*mapSlice, from, index)
+		return nil
+	})
+}
+
+func (o *RoughYaml) moveRelative(key string, anchorKey string, offset int) error {
+	mapSlice, err := o.mapping()
+	if err != nil {
+		return err
+	}
+	from := findKeyIndex(*mapSlice, key)
+	if from < 0 {
+		return fmt.Errorf("key '%v' not found under %v", key, describePath(o.path))
+	}
+	anchor := findKeyIndex(*mapSlice, anchorKey)
+	if anchor < 0 {
+		return fmt.Errorf("key '%v' not found under %v", anchorKey, describePath(o.path))
+	}
+	if from == anchor {
+		return nil
+	}
+	if from < anchor {
+		anchor--
+	}
+	return o.recordEdit(false, Change{Operation: OperationReorder, Path: o.Path()}, nil, func() error {
+		moveItem(*mapSlice, from, anchor+offset)
+		return nil
+	})
+}
+
+// moveItem moves the item at from to to, shifting the items between them.
+func moveItem(mapSlice yaml.MapSlice, from int, to int) {
+	item := mapSlice[from]
+	if from < to {
+		copy(mapSlice[from:to], mapSlice[from+1:to+1])
+	} else {
+		copy(mapSlice[to+1:from+1], mapSlice[to:from])
+	}
+	mapSlice[to] = item
+}
+
+// SetForceAt replaces the value of key, adding the key at index if it does not exist.
+// An existing key keeps its position.
+func (o *RoughYaml) SetForceAt(index int, key string, value interface{}) error {
+	if o.err != nil {
+		return o.err
+	}
+	if o.currentItem == nil {
+		return ErrDetachedNode
+	}
+	if child := o.Get(key); child.currentItem != nil {
+		change := Change{Operation: OperationReplace, Path: child.Path(), OldValue: child.Value(), NewValue: value}
+		return o.recordEdit(false, change, []interface{}{child.Key()}, func() error {
+			setContentsValue(child, value)
+			return nil
+		})
+	}
+	mapSlice := &yaml.MapSlice{}
+	if o.GetContents() != nil {
+		var err error
+		if mapSlice, err = o.mapping(); err != nil {
+			return err
+		}
+	}
+	if index < 0 || index > len(*mapSlice) {
+		return fmt.Errorf("index %v out of range (len %v) under %v", index, len(*mapSlice), describePath(o.path))
+	}
+	newMapSlice := make(yaml.MapSlice, 0, len(*mapSlice)+1)
+	newMapSlice = append(newMapSlice, (*mapSlice)[:index]...)
+	newMapSlice = append(newMapSlice, yaml.MapItem{Key: key, Value: value})
+	newMapSlice = append(newMapSlice, (*mapSlice)[index:]...)
+	change := Change{Operation: OperationAdd, Path: appendPath(o.path, key), NewValue: value}
+	return o.recordEdit(false, change, []interface{}{key}, func() error {
+		setContentsValue(o, &newMapSlice)
+		return nil
+	})
+}
+
+// InsertBefore adds key with value just before existingKey. It returns an error if key already exists.
+func (o *RoughYaml) InsertBefore(existingKey string, key string, value interface{}) error {
+	mapSlice, err := o.mapping()
+	if err != nil {
+		return err
+	}
+	index := findKeyIndex(*mapSlice, existingKey)
+	if index < 0 {
+		return fmt.Errorf("key '%v' not found under %v", existingKey, describePath(o.path))
+	}
+	if findKeyIndex(*mapSlice, key) >= 0 {
+		return fmt.Errorf("key '%v' already exists under %v", key, describePath(o.path))
+	}
+	return o.SetForceAt(index, key, value)
+}
+
+// mapping returns the contents of the node as a mapping.
+func (o *RoughYaml) mapping() (*yaml.MapSlice, error) {
+	if o.err != nil {
+		return nil, o.err
+	}
+	mapSlice, ok := o.GetContents().(*yaml.MapSlice)
+	if !ok {
+		return nil, fmt.Errorf("%v is not a mapping", describePath(o.path))
+	}
+	return mapSlice, nil
+}
@@ -0,0 +1,186 @@
+package goroughyaml
+
+import (
+	"testing"
+)
+
+func TestRenameKey(t *testing.T) {
+	//---------------------
+	// init
+	yamlString := `
+aaa:
+  bbb: bbb1
+  ccc: ccc1
+  ddd: ddd1
+`
+	var expectedValue interface{}
+	var actualValue interface{}
+
+	roughYamlObj := FromYaml(yamlString)
+
+	//
+	//
+	//---------------------
+	// success (rename keeps position)
+	roughYamlObj.Get("aaa").RenameKey("ccc", "zzz")
+	expectedValue = `aaa:
+  bbb: bbb1
+  zzz: ccc1
+  ddd: ddd1
+`
+	actualValue, _ = roughYamlObj.ToYaml()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+
+	//
+	//
+	//---------------------
+	// error (duplicated or missing key)
+	if roughYamlObj.Get("aaa").RenameKey("bbb", "ddd") == nil || roughYamlObj.Get("aaa").RenameKey("xxx", "yyy") == nil {
+		t.Errorf("<< FAILED >>>")
+	}
+}
+
+func TestMoveKey(t *testing.T) {
+	//---------------------
+	// init
+	yamlString := `
+aaa: 1
+bbb: 2
+ccc: 3
+ddd: 4
+`
+	var expectedValue interface{}
+	var actualValue interface{}
+
+	roughYamlObj := FromYaml(yamlString)
+
+	//
+	//
+	//---------------------
+	// success (move before)
+	roughYamlObj.MoveBefore("ddd", "bbb")
+	expectedValue = `aaa: 1
+ddd: 4
+bbb: 2
+ccc: 3
+`
+	actualValue, _ = roughYamlObj.ToYaml()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+
+	//
+	//
+	//---------------------
+	// success (move after)
+	roughYamlObj.MoveAfter("aaa", "ccc")
+	expectedValue = `ddd: 4
+bbb: 2
+ccc: 3
+aaa: 1
+`
+	actualValue, _ = roughYamlObj.ToYaml()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+
+	//
+	//
+	//---------------------
+	// success (move to index)
+	roughYamlObj.MoveToIndex("ddd", 3)
+	roughYamlObj.MoveToIndex("aaa", 0)
+	expectedValue = `aaa: 1
+bbb: 2
+ccc: 3
+ddd: 4
+`
+	actualValue, _ = roughYamlObj.ToYaml()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+
+	//
+	//
+	//---------------------
+	// error (out of range)
+	if roughYamlObj.MoveToIndex("aaa", 4) == nil || roughYamlObj.MoveBefore("aaa", "xxx") == nil {
+		t.Errorf("<< FAILED >>>")
+	}
+}
+
+func TestSetForceAt(t *testing.T) {
+	//---------------------
+	// init
+	yamlString := `
+aaa:
+  bbb: bbb1
+  ddd: ddd1
+`
+	var expectedValue interface{}
+	var actualValue interface{}
+
+	roughYamlObj := FromYaml(yamlString)
+
+	//
+	//
+	//---------------------
+	// success (insert)
+	roughYamlObj.Get("aaa").SetForceAt(0, "aaa", "aaa1")
+	roughYamlObj.Get("aaa").InsertBefore("ddd", "ccc", "ccc1")
+	roughYamlObj.Get("aaa").SetForceAt(4, "eee", "eee1")
+	expectedValue = `aaa:
+  aaa: aaa1
+  bbb: bbb1
+  ccc: ccc1
+  ddd: ddd1
+  eee: eee1
+`
+	actualValue, _ = roughYamlObj.ToYaml()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+
+	//
+	//
+	//---------------------
+	// success (existing key keeps position)
+	roughYamlObj.Get("aaa").SetForceAt(0, "ddd", "ddd2")
+	expectedValue = "ddd2"
+	actualValue = roughYamlObj.Get("aaa").Get("ddd").Value()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+	}
+	t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+
+	//
+	//
+	//---------------------
+	// error (existing key, out of range)
+	if roughYamlObj.Get("aaa").InsertBefore("ddd", "bbb", 1) == nil || roughYamlObj.Get("aaa").SetForceAt(9, "fff", 1) == nil {
+		t.Errorf("<< FAILED >>>")
+	}
+}
+
+func TestEditDetachedNode(t *testing.T) {
+	//---------------------
+	// init
+	var roughYamlObj RoughYaml
+
+	//
+	//
+	//---------------------
+	// error
+	for _, err := range []error{roughYamlObj.SetForceAt(0, "aaa", 1), roughYamlObj.Delete("aaa")} {
+		if err != ErrDetachedNode {
+			t.Errorf("<< FAILED >>> : %v", err)
+		}
+	}
+}
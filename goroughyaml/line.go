@@ -0,0 +1,121 @@
+package goroughyaml
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Line returns the line of the node in the yaml text which the document was read from, starting at 1.
+// It returns 0 when the line is not known: for documents which were not read by FromYaml or LoadFile,
+// for nodes added by edits, and for nodes in flow style such as [a, b].
+// Lines are found by scanning the indentation of the text, so unusual layouts may not be recognized.
+func (o *RoughYaml) Line() int {
+	if o.doc == nil || o.currentItem == nil {
+		return 0
+	}
+	return o.doc.line(o.path)
+}
+
+// line returns the line of the node at path, or 0 if it is not known.
+func (d *document) line(path []string) int {
+	d.linesOnce.Do(func() {
+		d.lines = indexLines(d.text)
+		d.text = ""
+	})
+	return d.lines[sourceKey(path)]
+}
+
+// lineFrame is a block mapping or sequence whose entries start at col.
+type lineFrame struct {
+	col        int
+	path       []string
+	isSequence bool
+	count      int
+}
+
+// indexLines returns the lines of the block style nodes of text by their sourceKey.
+func indexLines(text string) map[string]int {
+	lines := map[string]int{}
+	frames := []*lineFrame{{col: -1}}
+	var last []string
+	blockCol := -1
+	for number, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		col := len(line) - len(trimmed)
+		if blockCol >= 0 {
+			if trimmed == "" || col > blockCol {
+				continue
+			}
+			blockCol = -1
+		}
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "---") || strings.HasPrefix(trimmed, "...") {
+			continue
+		}
+		for trimmed != "" {
+			isDash := trimmed == "-" || strings.HasPrefix(trimmed, "- ")
+			key, rest, isKey := splitLineKey(trimmed)
+			if !isDash && !isKey {
+				break
+			}
+			for len(frames) > 1 && (frames[len(frames)-1].col > col || (isKey && frames[len(frames)-1].col == col && frames[len(frames)-1].isSequence && len(frames) > 2 && frames[len(frames)-2].col == col)) {
+				frames = frames[:len(frames)-1]
+			}
+			top := frames[len(frames)-1]
+			if top.col < col || (isDash && !top.isSequence) {
+				top = &lineFrame{col: col, path: last, isSequence: isDash}
+				frames = append(frames, top)
+			}
+			var path []string
+			if isDash {
+				path = appendPath(top.path, strconv.Itoa(top.count))
+				top.count++
+			} else {
+				path = appendPath(top.path, key)
+			}
+			lines[sourceKey(path)] = number + 1
+			last = path
+			if isDash {
+				rest = strings.TrimLeft(strings.TrimPrefix(trimmed, "-"), " ")
+			}
+			if strings.HasPrefix(rest, "|") || strings.HasPrefix(rest, ">") {
+				blockCol = col
+				break
+			}
+			if !isDash {
+				break
+			}
+			col += len(trimmed) - len(rest)
+			trimmed = rest
+		}
+	}
+	return lines
+}
+
+// splitLineKey splits a line which starts with a mapping key into the key and the rest of the line after ':'.
+func splitLineKey(line string) (string, string, bool) {
+	if strings.HasPrefix(line, "\"") || strings.HasPrefix(line, "'") {
+		end := strings.Index(line[1:], line[:1])
+		if end < 0 {
+			return "", "", false
+		}
+		key, rest := line[1:end+1], line[end+2:]
+		if rest != ":" && !strings.HasPrefix(rest, ": ") {
+			return "", "", false
+		}
+		return key, strings.TrimLeft(rest[1:], " "), true
+	}
+	if strings.HasPrefix(line, "[") || strings.HasPrefix(line, "{") || strings.HasPrefix(line, "- ") {
+		return "", "", false
+	}
+	index := strings.Index(line, ": ")
+	if index < 0 {
+		if !strings.HasSuffix(line, ":") {
+			return "", "", false
+		}
+		index = len(line) - 1
+	}
+	if hash := strings.Index(line, " #"); hash >= 0 && hash < index {
+		return "", "", false
+	}
+	return strings.TrimRight(line[:index], " "), strings.TrimLeft(line[index+1:], " "), true
+}
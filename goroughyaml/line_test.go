@@ -0,0 +1,55 @@
+package goroughyaml
+
+import (
+	"testing"
+)
+
+func TestLine(t *testing.T) {
+	//---------------------
+	// init
+	yamlString := `# config
+aaa:
+  bbb: bbb1
+  ccc:
+  - ccc1
+  - ddd: ddd1
+    eee: |
+      text
+      fff: not a key
+  ggg: ggg1
+"hhh": [1, 2]
+iii:
+  - - jjj
+`
+	var expectedValue interface{}
+	var actualValue interface{}
+
+	roughYamlObj := FromYaml(yamlString)
+
+	//
+	//
+	//---------------------
+	// success (block style nodes)
+	for _, testCase := range []struct {
+		path []string
+		line int
+	}{
+		{[]string{"aaa"}, 2},
+		{[]string{"aaa", "bbb"}, 3},
+		{[]string{"aaa", "ccc", "0"}, 5},
+		{[]string{"aaa", "ccc", "1"}, 6},
+		{[]string{"aaa", "ccc", "1", "ddd"}, 6},
+		{[]string{"aaa", "ccc", "1", "eee"}, 7},
+		{[]string{"aaa", "ggg"}, 10},
+		{[]string{"hhh"}, 11},
+		{[]string{"iii", "0", "0"}, 13},
+		{[]string{"hhh", "0"}, 0},
+	} {
+		expectedValue = testCase.line
+		actualValue = roughYamlObj.GetPath(testCase.path...).Line()
+		if actualValue != expectedValue {
+			t.Errorf("<< FAILED >>> : %v", testCase.path)
+		}
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+}
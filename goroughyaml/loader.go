@@ -0,0 +1,295 @@
+package goroughyaml
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Loader builds one document from layers of configuration.
+// Each layer overrides the values of the layers added before it: mappings are merged key by key,
+// and any other value, including a sequence, replaces the previous one.
+// Keys keep the order in which they first appear, and new keys are added at the end of their mapping.
+type Loader struct {
+	layers []func(root *yaml.MapSlice, sources map[string]string) error
+}
+
+// NewLoader returns a loader without layers.
+func NewLoader() *Loader {
+	return &Loader{}
+}
+
+// AddDefaults adds yamlContent, such as defaults embedded in the program. Its source is "defaults".
+func (l *Loader) AddDefaults(yamlContent string) *Loader {
+	l.layers = append(l.layers, func(root *yaml.MapSlice, sources map[string]string) error {
+		mapSlice := yaml.MapSlice{}
+		if err := yaml.Unmarshal([]byte(yamlContent), &mapSlice); err != nil {
+			return fmt.Errorf("defaults: %v", err)
+		}
+		mergeMapping(root, nil, mapSlice, "defaults", nil, sources)
+		return nil
+	})
+	return l
+}
+
+// AddFile adds the yaml file at path. Its source is the path. Load fails if the file does not exist.
+func (l *Loader) AddFile(path string) *Loader {
+	return l.addFile(path, false)
+}
+
+// AddOptionalFile adds the yaml file at path, if it exists.
+func (l *Loader) AddOptionalFile(path string) *Loader {
+	return l.addFile(path, true)
+}
+
+func (l *Loader) addFile(path string, optional bool) *Loader {
+	l.layers = append(l.layers, func(root *yaml.MapSlice, sources map[string]string) error {
+		bytes, err := ioutil.ReadFile(path)
+		if err != nil {
+			if optional && os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		mapSlice := yaml.MapSlice{}
+		if err := yaml.Unmarshal(bytes, &mapSlice); err != nil {
+			return fmt.Errorf("%v: %v", path, err)
+		}
+		mergeMapping(root, nil, mapSlice, path, nil, sources)
+		return nil
+	})
+	return l
+}
+
+// AddEnv adds the environment variables whose names start with prefix, such as "APP_".
+// The rest of a name is matched against the keys loaded so far, ignoring case and treating
+// "-" and "." in keys as "_", so that APP_DATABASE_HOST sets database.host and APP_LOG_LEVEL sets log-level.
+// Where no key matches, the rest of the name in lower case is used as a new key.
+// Values are parsed as yaml, so "5432" is a number and "true" is a boolean.
+// The source of a value is "env:" followed by the name of the variable.
+func (l *Loader) AddEnv(prefix string) *Loader {
+	l.layers = append(l.layers, func(root *yaml.MapSlice, sources map[string]string) error {
+		environ := os.Environ()
+		sort.Strings(environ)
+		for _, variable := range environ {
+			name, value := splitAssignment(variable)
+			if !strings.HasPrefix(name, prefix) || len(name) == len(prefix) {
+				continue
+			}
+			path := envPath(root, strings.ToLower(name[len(prefix):]))
+			assignValue(root, path, parseYamlValue(value))
+			recordSource(sources, path, "env:"+name)
+		}
+		return nil
+	})
+	return l
+}
+
+// AddSets adds assignments in the form "a.b=c", as given with --set on the command line.
+// Sequence elements are addressed by index, as in "servers.0.port=8080".
+// Values are parsed as yaml. The source of a value is "--set" followed by its path.
+func (l *Loader) AddSets(assignments ...string) *Loader {
+	l.layers = append(l.layers, func(root *yaml.MapSlice, sources map[string]string) error {
+		for _, assignment := range assignments {
+			key, value := splitAssignment(assignment)
+			if key == "" || len(key) == len(assignment) {
+				return fmt.Errorf("invalid --set %q: want key=value", assignment)
+			}
+			path := strings.Split(key, ".")
+			assignValue(root, path, parseYamlValue(value))
+			recordSource(sources, path, "--set "+key)
+		}
+		return nil
+	})
+	return l
+}
+
+// Load builds the document from the layers in the order they were added.
+func (l *Loader) Load() (RoughYaml, error) {
+	root := &yaml.MapSlice{}
+	sources := map[string]string{}
+	for _, layer := range l.layers {
+		if err := layer(root, sources); err != nil {
+			return newRoughYaml(&yaml.MapSlice{}), err
+		}
+	}
+	loaded := newRoughYaml(root)
+	loaded.doc.sources = sources
+	return loaded, nil
+}
+
+// SetFlags collects repeated command-line flags such as --set a.b=c for Loader.AddSets.
+//
+//	var sets goroughyaml.SetFlags
+//	flag.Var(&sets, "set", "override a value (key=value)")
+type SetFlags []string
+
+func (s *SetFlags) String() string {
+	return strings.Join(*s, ",")
+}
+
+// Set adds an assignment.
+func (s *SetFlags) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// Source returns the layer of a Loader which set the value of the node, such as "defaults", a file path,
+// "env:APP_DATABASE_HOST" or "--set database.host". A mapping merged from several layers has the source
+// of the layer which created it. It returns "" for documents which were not built by a Loader.
+// Edits after loading do not change sources.
+func (o *RoughYaml) Source() string {
+	if o.doc == nil {
+		return ""
+	}
+	return lookupSource(o.doc.sources, o.path, "")
+}
+
+// lookupSource returns the source recorded for path or its nearest ancestor, or fallback if there is none.
+func lookupSource(sources map[string]string, path []string, fallback string) string {
+	for length := len(path); length >= 0; length-- {
+		if source, ok := sources[sourceKey(path[:length])]; ok {
+			return source
+		}
+	}
+	return fallback
+}
+
+func sourceKey(path []string) string {
+	return strings.Join(path, "\x00")
+}
+
+// recordSource records source for path, dropping the sources of the values it replaced below path.
+func recordSource(sources map[string]string, path []string, source string) {
+	prefix := sourceKey(path) + "\x00"
+	for key := range sources {
+		if strings.HasPrefix(key, prefix) {
+			delete(sources, key)
+		}
+	}
+	sources[sourceKey(path)] = source
+}
+
+// mergeMapping merges src into the mapping dst at path.
+// The sources of the values of src are looked up in srcSources, which is keyed by their paths in the document,
+// falling back to source.
+func mergeMapping(dst *yaml.MapSlice, path []string, src yaml.MapSlice, source string, srcSources map[string]string, sources map[string]string) {
+	for _, item := range src {
+		childPath := appendPath(path, keyString(item.Key))
+		index := findKeyIndex(*dst, item.Key)
+		if srcMapSlice, ok := plainValue(item.Value).(yaml.MapSlice); ok && index >= 0 {
+			if dstMapSlice, ok := plainValue((*dst)[index].Value).(yaml.MapSlice); ok {
+				(*dst)[index].Value = &dstMapSlice
+				mergeMapping(&dstMapSlice, childPath, srcMapSlice, source, srcSources, sources)
+				continue
+			}
+		}
+		if index >= 0 {
+			(*dst)[index].Value = cloneValue(item.Value)
+		} else {
+			*dst = append(*dst, yaml.MapItem{Key: item.Key, Value: cloneValue(item.Value)})
+		}
+		recordSource(sources, childPath, lookupSource(srcSources, childPath, source))
+		prefix := sourceKey(childPath) + "\x00"
+		for key, keySource := range srcSources {
+			if strings.HasPrefix(key, prefix) {
+				sources[key] = keySource
+			}
+		}
+	}
+}
+
+// assignValue sets value at path below container, creating mappings where path does not exist,
+// and returns the updated container.
+func assignValue(container interface{}, path []string, value interface{}) interface{} {
+	if len(path) == 0 {
+		return value
+	}
+	switch v := container.(type) {
+	case yaml.MapSlice:
+		return assignValue(&v, path, value)
+	case *yaml.MapSlice:
+		if v == nil {
+			break
+		}
+		if index := findKeyIndex(*v, path[0]); index >= 0 {
+			(*v)[index].Value = assignValue((*v)[index].Value, path[1:], value)
+		} else {
+			*v = append(*v, yaml.MapItem{Key: path[0], Value: assignValue(nil, path[1:], value)})
+		}
+		return v
+	case []interface{}:
+		if index, ok := sequenceIndex(path[0]); ok && index >= 0 && index <= len(v) {
+			if index == len(v) {
+				v = append(v, nil)
+			}
+			v[index] = assignValue(v[index], path[1:], value)
+			return v
+		}
+	}
+	return assignValue(&yaml.MapSlice{}, path, value)
+}
+
+// envPath returns the path of the keys below container which name, in lower case without a prefix, stands for.
+// At each level, the longest matching key wins.
+func envPath(container interface{}, name string) []string {
+	var keys []string
+	switch v := plainValue(container).(type) {
+	case yaml.MapSlice:
+		for _, item := range v {
+			keys = append(keys, keyString(item.Key))
+		}
+	case []interface{}:
+		for index := range v {
+			keys = append(keys, keyString(index))
+		}
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		return len(keys[i]) > len(keys[j])
+	})
+	for _, key := range keys {
+		normalized := strings.NewReplacer("-", "_", ".", "_").Replace(strings.ToLower(key))
+		if name == normalized {
+			return []string{key}
+		}
+		if strings.HasPrefix(name, normalized+"_") {
+			child := plainValue(container)
+			if mapSlice, ok := child.(yaml.MapSlice); ok {
+				child = mapSlice[findKeyIndex(mapSlice, key)].Value
+			} else if index, ok := sequenceIndex(key); ok {
+				child = child.([]interface{})[index]
+			}
+			return append([]string{key}, envPath(child, name[len(normalized)+1:])...)
+		}
+	}
+	return []string{name}
+}
+
+// parseYamlValue parses a value given as text, such as an environment variable, as yaml.
+// Text which is not valid yaml, and the empty string, are taken as they are.
+func parseYamlValue(text string) interface{} {
+	if text == "" {
+		return text
+	}
+	var value interface{}
+	if err := yaml.Unmarshal([]byte(text), &value); err != nil {
+		return text
+	}
+	if _, ok := value.(map[interface{}]interface{}); ok {
+		mapSlice := yaml.MapSlice{}
+		yaml.Unmarshal([]byte(text), &mapSlice)
+		return &mapSlice
+	}
+	return value
+}
+
+func splitAssignment(assignment string) (string, string) {
+	index := strings.Index(assignment, "=")
+	if index < 0 {
+		return assignment, ""
+	}
+	return assignment[:index], assignment[index+1:]
+}
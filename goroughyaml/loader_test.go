@@ -0,0 +1,115 @@
+package goroughyaml
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoader(t *testing.T) {
+	//---------------------
+	// init
+	dir, err := ioutil.TempDir("", "goroughyaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.yaml")
+	ioutil.WriteFile(path, []byte(`
+database:
+  port: 5433
+  options: [ssl]
+name: file
+`), 0600)
+	os.Setenv("GOROUGHYAMLTEST_DATABASE_HOST", "db.example.com")
+	os.Setenv("GOROUGHYAMLTEST_LOG_LEVEL", "debug")
+	os.Setenv("GOROUGHYAMLTEST_EXTRA_KEY", "true")
+	defer os.Unsetenv("GOROUGHYAMLTEST_DATABASE_HOST")
+	defer os.Unsetenv("GOROUGHYAMLTEST_LOG_LEVEL")
+	defer os.Unsetenv("GOROUGHYAMLTEST_EXTRA_KEY")
+
+	var sets SetFlags
+	flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+	flagSet.Var(&sets, "set", "")
+	flagSet.Parse([]string{"--set", "database.port=6000", "--set=servers.0.name=web2"})
+
+	var expectedValue interface{}
+	var actualValue interface{}
+
+	roughYamlObj, err := NewLoader().
+		AddDefaults(`
+name: defaults
+database:
+  host: localhost
+  port: 5432
+  options: [plain, fast]
+log-level: info
+servers:
+- name: web1
+`).
+		AddFile(path).
+		AddOptionalFile(filepath.Join(dir, "missing.yaml")).
+		AddEnv("GOROUGHYAMLTEST_").
+		AddSets(sets...).
+		Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	//
+	//
+	//---------------------
+	// success (merged in order, new keys at the end)
+	expectedValue = `name: file
+database:
+  host: db.example.com
+  port: 6000
+  options:
+  - ssl
+log-level: debug
+servers:
+- name: web2
+extra_key: true
+`
+	actualValue, _ = roughYamlObj.ToYaml()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+
+	//
+	//
+	//---------------------
+	// success (sources)
+	for _, testCase := range []struct {
+		path   []string
+		source string
+	}{
+		{[]string{"name"}, path},
+		{[]string{"database"}, "defaults"},
+		{[]string{"database", "host"}, "env:GOROUGHYAMLTEST_DATABASE_HOST"},
+		{[]string{"database", "port"}, "--set database.port"},
+		{[]string{"database", "options", "0"}, path},
+		{[]string{"log-level"}, "env:GOROUGHYAMLTEST_LOG_LEVEL"},
+	} {
+		expectedValue = testCase.source
+		actualValue = roughYamlObj.GetPath(testCase.path...).Source()
+		if actualValue != expectedValue {
+			t.Errorf("<< FAILED >>>")
+		}
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+
+	//
+	//
+	//---------------------
+	// error (missing file, invalid assignment)
+	if _, err := NewLoader().AddFile(filepath.Join(dir, "missing.yaml")).Load(); err == nil {
+		t.Errorf("<< FAILED >>>")
+	}
+	if _, err := NewLoader().AddSets("database.port").Load(); err == nil {
+		t.Errorf("<< FAILED >>>")
+	}
+}
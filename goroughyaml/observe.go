@@ -0,0 +1,112 @@
+package goroughyaml
+
+import (
+	"gopkg.in/yaml.v2"
+	"strings"
+)
+
+// Operation is the kind of a Change.
+type Operation string
+
+const (
+	// OperationAdd adds a key to a mapping.
+	OperationAdd Operation = "add"
+	// OperationReplace replaces the value of a key or a sequence element.
+	// Undo, Redo and Rollback report the values they restore as added, replaced or deleted,
+	// and the mappings whose order they restore as replaced.
+	OperationReplace Operation = "replace"
+	// OperationDelete removes a key from a mapping.
+	OperationDelete Operation = "delete"
+	// OperationRename renames a key, keeping its value.
+	OperationRename Operation = "rename"
+	// OperationReorder changes the order of the keys of a mapping, as MoveBefore and SortKeys do.
+	OperationReorder Operation = "reorder"
+)
+
+// Change describes an edit of a document.
+type Change struct {
+	Operation Operation
+	// Path is the path of the changed node. For OperationReorder, it is the path of the mapping.
+	Path []string
+	// From is the path of the key before an OperationRename.
+	From []string
+	// OldValue is the value before the edit, or nil for OperationAdd.
+	// For OperationReorder, it is the mapping before the edit.
+	OldValue interface{}
+	// NewValue is the value after the edit, or nil for OperationDelete.
+	// For OperationReorder, it is the mapping after the edit.
+	NewValue interface{}
+}
+
+type observer struct {
+	pattern []string
+	fn      func(Change)
+}
+
+// OnChange calls fn after each edit of the document of the node which touches pathPattern.
+// pathPattern is a dotted path from the root of the document, in which "*" matches one key
+// and "**" matches any number of keys, such as "database.*" or "servers.**.port".
+// An edit touches the pattern when it changes a node that the pattern matches, a node below it,
+// or a node above it, so that replacing "database" as a whole is reported to "database.*".
+// An empty pattern matches every edit.
+//
+// fn is called synchronously, must not change the document and must not keep
+// OldValue or NewValue beyond the call without copying them.
+// OnChange returns a function which removes the hook.
+// For a node which does not belong to a document, OnChange adds no hook and returns a function which does nothing.
+func (o *RoughYaml) OnChange(pathPattern string, fn func(Change)) func() {
+	if o.doc == nil {
+		return func() {}
+	}
+	var pattern []string
+	if pathPattern != "" {
+		pattern = strings.Split(pathPattern, ".")
+	}
+	added := &observer{pattern: pattern, fn: fn}
+	o.doc.observers = append(o.doc.observers, added)
+	return func() {
+		for index, registered := range o.doc.observers {
+			if registered == added {
+				o.doc.observers = append(o.doc.observers[:index:index], o.doc.observers[index+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// notify records change in the audit log and calls the hooks whose pattern touches the path of change.
+func (d *document) notify(change Change) {
+	d.audit.record(change)
+	container := isContainer(change.OldValue) || isContainer(change.NewValue)
+	for _, registered := range append([]*observer{}, d.observers...) {
+		if patternTouches(registered.pattern, change.Path, container) || (change.From != nil && patternTouches(registered.pattern, change.From, container)) {
+			registered.fn(change)
+		}
+	}
+}
+
+// patternTouches reports whether path is at, below or above a path which pattern matches.
+// With container, the value at path is a mapping or a sequence, which can hold a match of the rest of a "**" pattern.
+func patternTouches(pattern []string, path []string, container bool) bool {
+	if len(pattern) == 0 || len(path) == 0 {
+		return true
+	}
+	switch pattern[0] {
+	case "**":
+		// "**" absorbs the last key of path only if the value there can hold the rest of the pattern,
+		// so that "servers.**.port" is touched by replacing "servers.0" but not "servers.0.name" with a scalar
+		return patternTouches(pattern[1:], path, container) || ((len(path) > 1 || container) && patternTouches(pattern, path[1:], container))
+	case "*", path[0]:
+		return patternTouches(pattern[1:], path[1:], container)
+	}
+	return false
+}
+
+// isContainer reports whether value is a mapping or a sequence.
+func isContainer(value interface{}) bool {
+	switch plainValue(value).(type) {
+	case yaml.MapSlice, []interface{}:
+		return true
+	}
+	return false
+}
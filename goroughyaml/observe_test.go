@@ -0,0 +1,151 @@
+package goroughyaml
+
+import (
+	"gopkg.in/yaml.v2"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOnChange(t *testing.T) {
+	//---------------------
+	// init
+	yamlString := `
+database:
+  host: localhost
+  port: 5432
+servers:
+- name: web1
+  port: 80
+log: info
+`
+	var expectedValue interface{}
+	var actualValue interface{}
+
+	roughYamlObj := FromYaml(yamlString)
+	var changes []Change
+	remove := roughYamlObj.OnChange("database.*", func(change Change) {
+		changes = append(changes, change)
+	})
+
+	//
+	//
+	//---------------------
+	// success (set, add and delete under the pattern)
+	roughYamlObj.Get("database").Set("port", 5433)
+	roughYamlObj.Get("database").SetForce("user", "admin")
+	roughYamlObj.Get("database").Delete("host")
+	roughYamlObj.Set("log", "debug")
+	expectedValue = []Change{
+		{Operation: OperationReplace, Path: []string{"database", "port"}, OldValue: 5432, NewValue: 5433},
+		{Operation: OperationAdd, Path: []string{"database", "user"}, NewValue: "admin"},
+		{Operation: OperationDelete, Path: []string{"database", "host"}, OldValue: "localhost"},
+	}
+	actualValue = changes
+	if !reflect.DeepEqual(actualValue, expectedValue) {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+
+	//
+	//
+	//---------------------
+	// success (replacing a node above the pattern)
+	changes = nil
+	roughYamlObj.SetForce("database", "sqlite")
+	expectedValue = OperationReplace
+	if len(changes) != 1 || changes[0].Operation != expectedValue {
+		t.Errorf("<< FAILED >>>")
+	}
+	t.Logf("changes:%v\n", changes)
+
+	//
+	//
+	//---------------------
+	// success (removed hook is not called)
+	changes = nil
+	remove()
+	roughYamlObj.SetForce("database", "postgres")
+	if len(changes) != 0 {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("changes:%v\n", changes)
+	}
+
+	//
+	//
+	//---------------------
+	// success (sequence elements and "**")
+	var paths []string
+	roughYamlObj.OnChange("servers.**.port", func(change Change) {
+		paths = append(paths, strings.Join(change.Path, "."))
+	})
+	roughYamlObj.Get("servers").Get("0").Set("port", 8080)
+	roughYamlObj.Get("servers").Get("0").Set("name", "web2")
+	roughYamlObj.Get("servers").Set("0", &yaml.MapSlice{{Key: "name", Value: "web3"}, {Key: "port", Value: 80}})
+	expectedValue = []string{"servers.0.port", "servers.0"}
+	actualValue = paths
+	if !reflect.DeepEqual(actualValue, expectedValue) {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+}
+
+func TestOnChangeOperations(t *testing.T) {
+	//---------------------
+	// init
+	yamlString := `
+aaa:
+  ccc: ccc1
+  bbb: bbb1
+`
+	var expectedValue interface{}
+	var actualValue interface{}
+
+	roughYamlObj := FromYaml(yamlString)
+	var operations []Operation
+	roughYamlObj.OnChange("", func(change Change) {
+		operations = append(operations, change.Operation)
+	})
+
+	//
+	//
+	//---------------------
+	// success (rename, reorder and undo)
+	roughYamlObj.SetUndoLimit(-1)
+	roughYamlObj.Get("aaa").RenameKey("ccc", "ddd")
+	roughYamlObj.Get("aaa").SortKeys(false, nil)
+	roughYamlObj.Undo()
+	expectedValue = []Operation{OperationRename, OperationReorder, OperationReplace}
+	actualValue = operations
+	if !reflect.DeepEqual(actualValue, expectedValue) {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+
+	//
+	//
+	//---------------------
+	// success (failed edits are not reported)
+	operations = nil
+	roughYamlObj.Set("xxx", 1)
+	roughYamlObj.Delete("xxx")
+	if len(operations) != 0 {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("operations:%v\n", operations)
+	}
+}
+
+func TestOnChangeDetachedNode(t *testing.T) {
+	//---------------------
+	// init
+	var roughYamlObj RoughYaml
+
+	//
+	//
+	//---------------------
+	// success
+	remove := roughYamlObj.OnChange("", func(change Change) {
+		t.Errorf("<< FAILED >>> : %v", change)
+	})
+	remove()
+}
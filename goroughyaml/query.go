@@ -0,0 +1,568 @@
+package goroughyaml
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"strconv"
+	"strings"
+)
+
+// Query returns the nodes below o which match the JSONPath expression expr, such as
+// "$.development-teams.*.ranks[?(@ > 500)]", in document order. $ is o itself.
+// The nodes are live like the ones returned by Get, so that setting a value through them changes the document,
+// and their Path is the concrete path of each match.
+//
+// Supported are names (.name and ['name']), wildcards (.* and [*]), recursive descent (..),
+// indexes ([0] and [-1]), slices ([1:3] and [::2]), unions ([0,'name']) and filters ([?(@.size > 1 && @.name)]).
+// A filter compares the values of @ and $ paths with other paths or literals by ==, !=, <, <=, > and >=,
+// combines comparisons by &&, || and !, and checks that a path exists when it is not compared.
+func (o *RoughYaml) Query(expr string) ([]*RoughYaml, error) {
+	if o.err != nil {
+		return nil, o.err
+	}
+	parser := &queryParser{expr: expr}
+	query, err := parser.parseQuery('$')
+	if err == nil && parser.pos < len(expr) {
+		err = parser.fail("unexpected %q", expr[parser.pos:])
+	}
+	if err != nil {
+		return nil, err
+	}
+	return query.selectNodes(o, o), nil
+}
+
+// jsonPathQuery is a parsed JSONPath query, starting at the root ($) or at the current node of a filter (@).
+type jsonPathQuery struct {
+	relative bool
+	segments []querySegment
+}
+
+// querySegment selects the children of each node, or its descendants as well with descendant.
+type querySegment struct {
+	descendant bool
+	selectors  []querySelector
+}
+
+type selectorKind int
+
+const (
+	selectName selectorKind = iota
+	selectWildcard
+	selectIndex
+	selectSlice
+	selectFilter
+)
+
+type querySelector struct {
+	kind             selectorKind
+	name             string
+	index            int
+	start, end, step *int
+	filter           filterExpression
+}
+
+// selectNodes returns the nodes which q selects from node, with root as $.
+func (q *jsonPathQuery) selectNodes(node *RoughYaml, root *RoughYaml) []*RoughYaml {
+	nodes := []*RoughYaml{node}
+	for _, segment := range q.segments {
+		var selected []*RoughYaml
+		for _, current := range nodes {
+			targets := []*RoughYaml{current}
+			if segment.descendant {
+				targets = descendants(current, targets)
+			}
+			for _, target := range targets {
+				for _, selector := range segment.selectors {
+					selected = append(selected, selector.selectNodes(target, root)...)
+				}
+			}
+		}
+		nodes = selected
+	}
+	return nodes
+}
+
+// descendants appends the descendants of node to nodes in document order.
+func descendants(node *RoughYaml, nodes []*RoughYaml) []*RoughYaml {
+	for _, child := range children(node) {
+		nodes = descendants(child, append(nodes, child))
+	}
+	return nodes
+}
+
+// children returns the values of a mapping or the elements of a sequence as nodes.
+func children(node *RoughYaml) []*RoughYaml {
+	var nodes []*RoughYaml
+	switch contents := plainValue(node.GetContents()).(type) {
+	case yaml.MapSlice:
+		for _, item := range contents {
+			nodes = append(nodes, node.GetKey(item.Key))
+		}
+	case []interface{}:
+		for index := range contents {
+			nodes = append(nodes, node.GetKey(index))
+		}
+	}
+	return nodes
+}
+
+func (s *querySelector) selectNodes(node *RoughYaml, root *RoughYaml) []*RoughYaml {
+	contents := plainValue(node.GetContents())
+	switch s.kind {
+	case selectName:
+		if mapSlice, ok := contents.(yaml.MapSlice); ok {
+			if index := findKeyIndex(mapSlice, s.name); index >= 0 {
+				return []*RoughYaml{node.GetKey(mapSlice[index].Key)}
+			}
+		}
+	case selectWildcard:
+		return children(node)
+	case selectIndex:
+		if elements, ok := contents.([]interface{}); ok {
+			index := s.index
+			if index < 0 {
+				index += len(elements)
+			}
+			if index >= 0 && index < len(elements) {
+				return []*RoughYaml{node.GetKey(index)}
+			}
+		}
+	case selectSlice:
+		if elements, ok := contents.([]interface{}); ok {
+			var nodes []*RoughYaml
+			for _, index := range sliceIndexes(len(elements), s.start, s.end, s.step) {
+				nodes = append(nodes, node.GetKey(index))
+			}
+			return nodes
+		}
+	case selectFilter:
+		var nodes []*RoughYaml
+		for _, child := range children(node) {
+			if s.filter.test(child, root) {
+				nodes = append(nodes, child)
+			}
+		}
+		return nodes
+	}
+	return nil
+}
+
+// sliceIndexes returns the indexes selected by [start:end:step] in a sequence of length, as in Python.
+func sliceIndexes(length int, start, end, step *int) []int {
+	by := 1
+	if step != nil {
+		by = *step
+	}
+	if by == 0 {
+		return nil
+	}
+	normalize := func(index *int, otherwise int) int {
+		if index == nil {
+			return otherwise
+		}
+		if *index < 0 {
+			return *index + length
+		}
+		return *index
+	}
+	var indexes []int
+	if by > 0 {
+		lower, upper := normalize(start, 0), normalize(end, length)
+		if lower < 0 {
+			lower = 0
+		}
+		if upper > length {
+			upper = length
+		}
+		for index := lower; index < upper; index += by {
+			indexes = append(indexes, index)
+		}
+		return indexes
+	}
+	upper, lower := normalize(start, length-1), normalize(end, -1-length)
+	if upper > length-1 {
+		upper = length - 1
+	}
+	if lower < -1 {
+		lower = -1
+	}
+	for index := upper; index > lower; index += by {
+		indexes = append(indexes, index)
+	}
+	return indexes
+}
+
+// filterExpression is a condition of a filter selector, tested with each child as @.
+type filterExpression interface {
+	test(current *RoughYaml, root *RoughYaml) bool
+}
+
+type logicalExpression struct {
+	and         bool
+	left, right filterExpression
+}
+
+func (e *logicalExpression) test(current *RoughYaml, root *RoughYaml) bool {
+	if e.and {
+		return e.left.test(current, root) && e.right.test(current, root)
+	}
+	return e.left.test(current, root) || e.right.test(current, root)
+}
+
+type notExpression struct {
+	operand filterExpression
+}
+
+func (e *notExpression) test(current *RoughYaml, root *RoughYaml) bool {
+	return !e.operand.test(current, root)
+}
+
+// existsExpression is true if its query selects any node.
+type existsExpression struct {
+	query *jsonPathQuery
+}
+
+func (e *existsExpression) test(current *RoughYaml, root *RoughYaml) bool {
+	return len(e.query.evaluate(current, root)) > 0
+}
+
+type comparisonExpression struct {
+	operator    string
+	left, right filterOperand
+}
+
+// filterOperand is a literal, or a query whose value is compared if it selects exactly one node.
+type filterOperand struct {
+	query   *jsonPathQuery
+	literal interface{}
+}
+
+func (o filterOperand) value(current *RoughYaml, root *RoughYaml) (interface{}, bool) {
+	if o.query == nil {
+		return o.literal, true
+	}
+	nodes := o.query.evaluate(current, root)
+	if len(nodes) != 1 {
+		return nil, false
+	}
+	return plainValue(nodes[0].GetContents()), true
+}
+
+func (e *comparisonExpression) test(current *RoughYaml, root *RoughYaml) bool {
+	left, leftExists := e.left.value(current, root)
+	right, rightExists := e.right.value(current, root)
+	equal := leftExists == rightExists && (!leftExists || jsonEqual(left, right))
+	switch e.operator {
+	case "==":
+		return equal
+	case "!=":
+		return !equal
+	case "<":
+		return leftExists && rightExists && lessThan(left, right)
+	case "<=":
+		return leftExists && rightExists && (lessThan(left, right) || equal)
+	case ">":
+		return leftExists && rightExists && lessThan(right, left)
+	case ">=":
+		return leftExists && rightExists && (lessThan(right, left) || equal)
+	}
+	return false
+}
+
+// lessThan compares two numbers or two strings, and is false for values of other types.
+func lessThan(a interface{}, b interface{}) bool {
+	if x, ok := numberValue(a, false); ok {
+		y, ok := numberValue(b, false)
+		return ok && x < y
+	}
+	if x, ok := a.(string); ok {
+		y, ok := b.(string)
+		return ok && x < y
+	}
+	return false
+}
+
+func (q *jsonPathQuery) evaluate(current *RoughYaml, root *RoughYaml) []*RoughYaml {
+	if q.relative {
+		return q.selectNodes(current, root)
+	}
+	return q.selectNodes(root, root)
+}
+
+// queryParser parses a JSONPath expression from pos.
+type queryParser struct {
+	expr string
+	pos  int
+}
+
+func (p *queryParser) fail(format string, args ...interface{}) error {
+	return fmt.Errorf("invalid JSONPath %q at offset %v: %v", p.expr, p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *queryParser) peek() byte {
+	if p.pos < len(p.expr) {
+		return p.expr[p.pos]
+	}
+	return 0
+}
+
+func (p *queryParser) consume(token string) bool {
+	if strings.HasPrefix(p.expr[p.pos:], token) {
+		p.pos += len(token)
+		return true
+	}
+	return false
+}
+
+func (p *queryParser) skipSpaces() {
+	for p.pos < len(p.expr) && strings.IndexByte(" \t\r\n", p.expr[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// parseQuery parses a query starting with identifier, which is $ or @, followed by segments.
+func (p *queryParser) parseQuery(identifier byte) (*jsonPathQuery, error) {
+	if p.peek() != identifier {
+		return nil, p.fail("want %q", identifier)
+	}
+	p.pos++
+	query := &jsonPathQuery{relative: identifier == '@'}
+	for {
+		segment := querySegment{descendant: p.consume("..")}
+		switch {
+		case p.peek() == '[':
+			p.pos++
+			selectors, err := p.parseSelectors()
+			if err != nil {
+				return nil, err
+			}
+			segment.selectors = selectors
+		case segment.descendant || p.consume("."):
+			if p.consume("*") {
+				segment.selectors = []querySelector{{kind: selectWildcard}}
+				break
+			}
+			name := p.parseName()
+			if name == "" {
+				return nil, p.fail("want a name")
+			}
+			segment.selectors = []querySelector{{kind: selectName, name: name}}
+		default:
+			return query, nil
+		}
+		query.segments = append(query.segments, segment)
+	}
+}
+
+// parseName parses a name after a dot, which ends at a character with a meaning in JSONPath.
+func (p *queryParser) parseName() string {
+	start := p.pos
+	for p.pos < len(p.expr) && strings.IndexByte(".[]()=!<>&|,'\" \t\r\n", p.expr[p.pos]) < 0 {
+		p.pos++
+	}
+	return p.expr[start:p.pos]
+}
+
+// parseSelectors parses the comma separated selectors in brackets, after the opening bracket.
+func (p *queryParser) parseSelectors() ([]querySelector, error) {
+	var selectors []querySelector
+	for {
+		p.skipSpaces()
+		selector, err := p.parseSelector()
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, selector)
+		p.skipSpaces()
+		if p.consume("]") {
+			return selectors, nil
+		}
+		if !p.consume(",") {
+			return nil, p.fail("want ',' or ']'")
+		}
+	}
+}
+
+func (p *queryParser) parseSelector() (querySelector, error) {
+	switch c := p.peek(); {
+	case c == '\'' || c == '"':
+		name, err := p.parseString()
+		return querySelector{kind: selectName, name: name}, err
+	case c == '*':
+		p.pos++
+		return querySelector{kind: selectWildcard}, nil
+	case c == '?':
+		p.pos++
+		p.skipSpaces()
+		filter, err := p.parseOr()
+		return querySelector{kind: selectFilter, filter: filter}, err
+	}
+	start, err := p.parseOptionalInt()
+	if err != nil {
+		return querySelector{}, err
+	}
+	p.skipSpaces()
+	if !p.consume(":") {
+		if start == nil {
+			return querySelector{}, p.fail("want a selector")
+		}
+		return querySelector{kind: selectIndex, index: *start}, nil
+	}
+	selector := querySelector{kind: selectSlice, start: start}
+	p.skipSpaces()
+	if selector.end, err = p.parseOptionalInt(); err != nil {
+		return querySelector{}, err
+	}
+	p.skipSpaces()
+	if p.consume(":") {
+		p.skipSpaces()
+		if selector.step, err = p.parseOptionalInt(); err != nil {
+			return querySelector{}, err
+		}
+	}
+	return selector, nil
+}
+
+// parseOptionalInt parses an integer, or returns nil if there is none.
+func (p *queryParser) parseOptionalInt() (*int, error) {
+	start := p.pos
+	p.consume("-")
+	for p.pos < len(p.expr) && p.expr[p.pos] >= '0' && p.expr[p.pos] <= '9' {
+		p.pos++
+	}
+	if p.pos == start {
+		return nil, nil
+	}
+	number, err := strconv.Atoi(p.expr[start:p.pos])
+	if err != nil {
+		p.pos = start
+		return nil, p.fail("invalid integer")
+	}
+	return &number, nil
+}
+
+// parseString parses a string literal in single or double quotes, with backslash escapes.
+func (p *queryParser) parseString() (string, error) {
+	quote := p.expr[p.pos]
+	p.pos++
+	var text strings.Builder
+	for p.pos < len(p.expr) {
+		c := p.expr[p.pos]
+		p.pos++
+		switch {
+		case c == quote:
+			return text.String(), nil
+		case c == '\\' && p.pos < len(p.expr):
+			escaped := p.expr[p.pos]
+			p.pos++
+			switch escaped {
+			case 'n':
+				text.WriteByte('\n')
+			case 't':
+				text.WriteByte('\t')
+			case 'r':
+				text.WriteByte('\r')
+			default:
+				text.WriteByte(escaped)
+			}
+		default:
+			text.WriteByte(c)
+		}
+	}
+	return "", p.fail("unterminated string")
+}
+
+func (p *queryParser) parseOr() (filterExpression, error) {
+	left, err := p.parseAnd()
+	for err == nil {
+		p.skipSpaces()
+		if !p.consume("||") {
+			return left, nil
+		}
+		var right filterExpression
+		right, err = p.parseAnd()
+		left = &logicalExpression{left: left, right: right}
+	}
+	return nil, err
+}
+
+func (p *queryParser) parseAnd() (filterExpression, error) {
+	left, err := p.parseUnary()
+	for err == nil {
+		p.skipSpaces()
+		if !p.consume("&&") {
+			return left, nil
+		}
+		var right filterExpression
+		right, err = p.parseUnary()
+		left = &logicalExpression{and: true, left: left, right: right}
+	}
+	return nil, err
+}
+
+func (p *queryParser) parseUnary() (filterExpression, error) {
+	p.skipSpaces()
+	if p.peek() == '!' && !strings.HasPrefix(p.expr[p.pos:], "!=") {
+		p.pos++
+		operand, err := p.parseUnary()
+		return &notExpression{operand: operand}, err
+	}
+	if p.consume("(") {
+		expression, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpaces()
+		if !p.consume(")") {
+			return nil, p.fail("want ')'")
+		}
+		return expression, nil
+	}
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	for _, operator := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.consume(operator) {
+			p.skipSpaces()
+			right, err := p.parseOperand()
+			return &comparisonExpression{operator: operator, left: left, right: right}, err
+		}
+	}
+	if left.query == nil {
+		return nil, p.fail("want a comparison")
+	}
+	return &existsExpression{query: left.query}, nil
+}
+
+func (p *queryParser) parseOperand() (filterOperand, error) {
+	switch c := p.peek(); {
+	case c == '@' || c == '$':
+		query, err := p.parseQuery(c)
+		return filterOperand{query: query}, err
+	case c == '\'' || c == '"':
+		text, err := p.parseString()
+		return filterOperand{literal: text}, err
+	case c == '-' || c >= '0' && c <= '9':
+		start := p.pos
+		for p.pos < len(p.expr) && strings.IndexByte("+-.0123456789eE", p.expr[p.pos]) >= 0 {
+			p.pos++
+		}
+		number, err := strconv.ParseFloat(p.expr[start:p.pos], 64)
+		if err != nil {
+			p.pos = start
+			return filterOperand{}, p.fail("invalid number")
+		}
+		return filterOperand{literal: number}, nil
+	}
+	for _, literal := range []struct {
+		token string
+		value interface{}
+	}{{"true", true}, {"false", false}, {"null", nil}} {
+		if p.consume(literal.token) {
+			return filterOperand{literal: literal.value}, nil
+		}
+	}
+	return filterOperand{}, p.fail("want a path or a literal")
+}
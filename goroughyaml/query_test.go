@@ -0,0 +1,107 @@
+package goroughyaml
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestQuery(t *testing.T) {
+	//---------------------
+	// init
+	yamlString := `
+development-teams:
+  team-a:
+    lead: alice
+    ranks: [100, 1000, 600]
+  team-b:
+    ranks: [700, 50]
+  team-c:
+    lead: carol
+    ranks: []
+servers:
+- name: aaa
+  port: 80
+- name: bbb
+  port: 8080
+- name: ccc
+  port: 443
+`
+	var expectedValue interface{}
+	var actualValue interface{}
+
+	roughYamlObj := FromYaml(yamlString)
+	paths := func(expr string) interface{} {
+		nodes, err := roughYamlObj.Query(expr)
+		if err != nil {
+			return err.Error()
+		}
+		var paths []string
+		for _, node := range nodes {
+			paths = append(paths, fmt.Sprint(node.Path(), "=", node.Value()))
+		}
+		return paths
+	}
+
+	//
+	//
+	//---------------------
+	// success
+	for expr, expected := range map[string][]string{
+		"$.development-teams.*.ranks[?(@ > 500)]": {
+			"[development-teams team-a ranks 1]=1000",
+			"[development-teams team-a ranks 2]=600",
+			"[development-teams team-b ranks 0]=700",
+		},
+		"$..lead": {
+			"[development-teams team-a lead]=alice",
+			"[development-teams team-c lead]=carol",
+		},
+		"$.servers[1:3].name":                                            {"[servers 1 name]=bbb", "[servers 2 name]=ccc"},
+		"$.servers[::-2].port":                                           {"[servers 2 port]=443", "[servers 0 port]=80"},
+		"$.servers[-1, 0]['name']":                                       {"[servers 2 name]=ccc", "[servers 0 name]=aaa"},
+		"$['servers'][?(@.port >= 443 && @.name != 'ccc')].name":         {"[servers 1 name]=bbb"},
+		"$.development-teams[?(!@.lead)].ranks[0]":                       {"[development-teams team-b ranks 0]=700"},
+		"$.servers[?(@.name == $.servers[0].name || @.port < 100)].port": {"[servers 0 port]=80"},
+		"$.missing.*": nil,
+	} {
+		expectedValue = expected
+		actualValue = paths(expr)
+		if !reflect.DeepEqual(actualValue, expectedValue) {
+			t.Errorf("<< FAILED >>> : %v", expr)
+			t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+		}
+	}
+
+	//
+	//
+	//---------------------
+	// success (live nodes)
+	nodes, _ := roughYamlObj.Query("$.servers[?(@.port == 8080)]")
+	if err := nodes[0].Set("port", 9090); err != nil {
+		t.Errorf("<< FAILED >>> : %v", err)
+	}
+	expectedValue = 9090
+	actualValue = roughYamlObj.GetPath("servers", "1", "port").Value()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+
+	//
+	//
+	//---------------------
+	// error
+	for _, expr := range []string{"servers", "$.servers[", "$.servers[?(@.port >)]", "$..", "$.servers[0]x"} {
+		if _, err := roughYamlObj.Query(expr); err == nil {
+			t.Errorf("<< FAILED >>> : %v", expr)
+		}
+	}
+	_, err := roughYamlObj.Query("$.servers[?(@.port >)]")
+	expectedValue = `invalid JSONPath "$.servers[?(@.port >)]" at offset 20: want a path or a literal`
+	actualValue = err.Error()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+}
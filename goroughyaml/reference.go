@@ -0,0 +1,183 @@
+package goroughyaml
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"strings"
+)
+
+// ResolveReferences replaces references to other values of the document in the string values below the node.
+// A reference is a path from the root of the document, such as "${.server.host}" or "${.servers.0.port}".
+// A string which is one reference becomes a copy of the referenced value with its type, even a mapping;
+// otherwise the referenced scalars are written into the string, as in "http://${.server.host}:${.server.port}".
+// References to values which contain references are resolved first, and a reference cycle is an error.
+// "$${." is an escaped "${.", which is left as it is by ExpandEnv and becomes "${." here.
+// If any reference fails, ResolveReferences changes nothing and returns an ExpandErrors with the failures.
+func (o *RoughYaml) ResolveReferences() error {
+	if o.err != nil {
+		return o.err
+	}
+	if o.doc == nil {
+		return ErrDetachedNode
+	}
+	r := &referenceResolver{root: o.doc.rootNode(), entries: map[string]*referenceEntry{}}
+	o.doc.rootNode().walkStrings(func(parent *RoughYaml, key interface{}, text string) {
+		if strings.Contains(text, "${.") {
+			entry := &referenceEntry{parent: parent, key: key, path: appendPath(parent.path, keyString(key)), text: text}
+			r.entries[sourceKey(entry.path)] = entry
+			r.order = append(r.order, entry)
+		}
+	})
+	var targets []*referenceEntry
+	for _, entry := range r.order {
+		if hasPathPrefix(entry.path, o.path) {
+			r.resolve(entry)
+			targets = append(targets, entry)
+		}
+	}
+	if len(r.errs) > 0 {
+		return r.errs
+	}
+	for _, entry := range targets {
+		if err := entry.parent.SetKey(entry.key, entry.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type referenceResolver struct {
+	root    *RoughYaml
+	entries map[string]*referenceEntry
+	order   []*referenceEntry
+	stack   []string
+	errs    ExpandErrors
+}
+
+// referenceEntry is a string value which contains references.
+type referenceEntry struct {
+	parent *RoughYaml
+	key    interface{}
+	path   []string
+	text   string
+	state  referenceState
+	value  interface{}
+}
+
+type referenceState int
+
+const (
+	referenceUnresolved referenceState = iota
+	referenceResolving
+	referenceResolved
+	referenceFailed
+)
+
+// resolve computes the value of entry and reports whether it succeeded.
+// Only the first failure of a chain of references is recorded as an error.
+func (r *referenceResolver) resolve(entry *referenceEntry) bool {
+	switch entry.state {
+	case referenceResolving:
+		cycle := append(r.stack, strings.Join(entry.path, "."))
+		r.fail(entry.path, fmt.Sprintf("reference cycle: %v", strings.Join(cycle[indexOf(r.stack, strings.Join(entry.path, ".")):], " -> ")))
+		return false
+	case referenceResolved:
+		return true
+	case referenceFailed:
+		return false
+	}
+	entry.state = referenceResolving
+	r.stack = append(r.stack, strings.Join(entry.path, "."))
+	defer func() {
+		r.stack = r.stack[:len(r.stack)-1]
+	}()
+	builder := &strings.Builder{}
+	text := entry.text
+	for index := 0; index < len(text); {
+		if strings.HasPrefix(text[index:], "$${.") {
+			builder.WriteString("${.")
+			index += 4
+			continue
+		}
+		end := strings.IndexByte(text[index:], '}')
+		if !strings.HasPrefix(text[index:], "${.") || end < 0 {
+			builder.WriteByte(text[index])
+			index++
+			continue
+		}
+		reference := text[index : index+end+1]
+		path := strings.Split(reference[3:len(reference)-1], ".")
+		value, ok := r.lookup(entry, reference, path)
+		if !ok {
+			entry.state = referenceFailed
+			return false
+		}
+		if len(reference) == len(text) {
+			entry.value = value
+			entry.state = referenceResolved
+			return true
+		}
+		switch value.(type) {
+		case yaml.MapSlice, *yaml.MapSlice, []interface{}:
+			r.fail(entry.path, fmt.Sprintf("reference %v: %v is not a scalar", reference, describePath(path)))
+			entry.state = referenceFailed
+			return false
+		}
+		builder.WriteString(keyString(value))
+		index += end + 1
+	}
+	entry.value = builder.String()
+	entry.state = referenceResolved
+	return true
+}
+
+// lookup returns a copy of the value at path with the references in it resolved.
+func (r *referenceResolver) lookup(entry *referenceEntry, reference string, path []string) (interface{}, bool) {
+	for _, key := range path {
+		if key == "" {
+			r.fail(entry.path, fmt.Sprintf("reference %v: invalid path", reference))
+			return nil, false
+		}
+	}
+	target := r.root.GetPath(path...)
+	if target.err != nil {
+		r.fail(entry.path, fmt.Sprintf("reference %v: %v", reference, target.err))
+		return nil, false
+	}
+	value := cloneValue(plainValue(target.Value()))
+	for _, dependency := range r.order {
+		if !hasPathPrefix(dependency.path, path) {
+			continue
+		}
+		if !r.resolve(dependency) {
+			return nil, false
+		}
+		value = assignValue(value, dependency.path[len(path):], cloneValue(dependency.value))
+	}
+	return value, true
+}
+
+func (r *referenceResolver) fail(path []string, message string) {
+	r.errs = append(r.errs, ExpandError{Path: path, Message: message})
+}
+
+func hasPathPrefix(path []string, prefix []string) bool {
+	if len(path) < len(prefix) {
+		return false
+	}
+	for index := range prefix {
+		if path[index] != prefix[index] {
+			return false
+		}
+	}
+	return true
+}
+
+func indexOf(values []string, value string) int {
+	for index := range values {
+		if values[index] == value {
+			return index
+		}
+	}
+	return 0
+}
@@ -0,0 +1,108 @@
+package goroughyaml
+
+import (
+	"testing"
+)
+
+func TestResolveReferences(t *testing.T) {
+	//---------------------
+	// init
+	yamlString := `
+url: http://${.server.address}/
+server:
+  address: ${.server.host}:${.server.port}
+  host: localhost
+  port: 8080
+backup: ${.server}
+ports:
+- ${.server.port}
+note: costs $${.price}
+`
+	var expectedValue interface{}
+	var actualValue interface{}
+
+	//
+	//
+	//---------------------
+	// success (dependency order and typed values)
+	roughYamlObj := FromYaml(yamlString)
+	if err := roughYamlObj.ResolveReferences(); err != nil {
+		t.Errorf("<< FAILED >>> : %v", err)
+	}
+	expectedValue = `url: http://localhost:8080/
+server:
+  address: localhost:8080
+  host: localhost
+  port: 8080
+backup:
+  address: localhost:8080
+  host: localhost
+  port: 8080
+ports:
+- 8080
+note: costs ${.price}
+`
+	actualValue, _ = roughYamlObj.ToYaml()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+
+	//
+	//
+	//---------------------
+	// success (escaped references survive ExpandEnv)
+	roughYamlObj = FromYaml(`note: $${.price} and $$HOME`)
+	roughYamlObj.ExpandEnv(func(name string) (string, bool) { return "", false })
+	roughYamlObj.ResolveReferences()
+	expectedValue = "${.price} and $HOME"
+	actualValue = roughYamlObj.Get("note").Value()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+	}
+	t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+
+	//
+	//
+	//---------------------
+	// error (cycle and unresolved references, nothing is changed)
+	roughYamlObj = FromYaml(`
+aaa: ${.bbb}
+bbb: x${.aaa}
+ccc: ${.ddd.eee}
+fff: ${.ggg}
+ggg: ${.ccc}
+`)
+	err := roughYamlObj.ResolveReferences()
+	expectedValue = `'aaa': reference cycle: aaa -> bbb -> aaa
+'ccc': reference ${.ddd.eee}: key 'ddd' not found under the root`
+	if err == nil || err.Error() != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", err, expectedValue)
+	}
+	expectedValue = "${.ccc}"
+	actualValue = roughYamlObj.Get("ggg").Value()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+	}
+	t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+}
+
+func TestResolveReferencesDetachedNode(t *testing.T) {
+	//---------------------
+	// init
+	var roughYamlObj RoughYaml
+	var expectedValue interface{}
+	var actualValue interface{}
+
+	//
+	//
+	//---------------------
+	// error
+	expectedValue = ErrDetachedNode
+	actualValue = roughYamlObj.ResolveReferences()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+}
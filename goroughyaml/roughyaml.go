@@ -40,6 +40,12 @@
 //
 //	roughYaml.Get("xxx").Value()) // => nil
 //
+// Check errors
+//
+//	roughYaml.Get("xxx").Get("yyy").Err() // => key 'xxx' not found under the root
+//	roughYaml.Get("ddd").Get("bbb").Get("5").Err() // => index 5 out of range (len 2) under 'ddd.bbb'
+//	roughYaml.Get("xxx").Set("yyy", 1) // => key 'xxx' not found under the root
+//
 // Set value
 //
 //	roughYaml.Get("aaa").Set("yyy", nil)
@@ -94,36 +100,60 @@ import (
 	"gopkg.in/yaml.v2"
 	"reflect"
 	"strconv"
+	"strings"
 )
 
-type roughYaml struct {
+// RoughYaml is a node of a yaml document.
+// The value returned by FromYaml is the root node, and Get returns its descendants.
+type RoughYaml struct {
 	contents            interface{}
 	currentItem         *yaml.MapItem
 	isListCurrentItem   bool
 	currentIndex        int
 	liseSizeCurrentItem int
+	slot                *interface{}
+	path                []string
+	keys                []interface{}
+	err                 error
+	file                *loadedFile
+	doc                 *document
+}
+
+// Node is the read-only view of a RoughYaml.
+// It is implemented by *RoughYaml, so that code which only reads a document can accept a Node and be tested with a mock.
+type Node interface {
+	Key() interface{}
+	Value() interface{}
+	GetContents() interface{}
+	ToYaml() (string, error)
+	Lookup(key string) Node
 }
 
-func FromYaml(yamlContent string) roughYaml {
+var _ Node = (*RoughYaml)(nil)
+
+func FromYaml(yamlContent string) RoughYaml {
 	mapSlice := &yaml.MapSlice{}
 	yaml.Unmarshal([]byte(yamlContent), mapSlice)
-	return newRoughYaml(mapSlice)
+	roughYaml := newRoughYaml(mapSlice)
+	roughYaml.doc.text = yamlContent
+	return roughYaml
 }
 
-func newRoughYaml(yamlData interface{}) roughYaml {
+func newRoughYaml(yamlData interface{}) RoughYaml {
 	rootMapItem := yaml.MapItem{Key: "root", Value: yamlData}
-	orderedMapSlice := roughYaml{
+	orderedMapSlice := RoughYaml{
 		contents:            yamlData,
 		currentItem:         &rootMapItem,
 		isListCurrentItem:   isList(yamlData),
 		currentIndex:        -1,
 		liseSizeCurrentItem: getSize(yamlData),
+		doc:                 &document{root: &rootMapItem},
 	}
 	return orderedMapSlice
 }
 
-func createRoughYaml(yamlContents interface{}, item *yaml.MapItem) *roughYaml {
-	return &roughYaml{
+func createRoughYaml(yamlContents interface{}, item *yaml.MapItem) *RoughYaml {
+	return &RoughYaml{
 		contents:            yamlContents,
 		currentItem:         item,
 		isListCurrentItem:   isList(yamlContents),
@@ -132,14 +162,14 @@ func createRoughYaml(yamlContents interface{}, item *yaml.MapItem) *roughYaml {
 	}
 }
 
-func createRoughYamlNil() *roughYaml {
+func createRoughYamlNil() *RoughYaml {
 	return createRoughYaml(nil, nil)
 }
 
 func isList(value interface{}) bool {
 	contents := getContents(value)
 	slice, ok := contents.(*interface{})
-	if ok {
+	if ok && *slice != nil {
 		switch reflect.TypeOf(*slice).Kind() {
 		case reflect.Slice:
 			return true
@@ -151,7 +181,7 @@ func isList(value interface{}) bool {
 func getSize(value interface{}) int {
 	contents := getContents(value)
 	slice, ok := contents.(*interface{})
-	if ok {
+	if ok && *slice != nil {
 		switch reflect.TypeOf(*slice).Kind() {
 		case reflect.Slice:
 			s := reflect.ValueOf(*slice)
@@ -161,7 +191,7 @@ func getSize(value interface{}) int {
 	return 0
 }
 
-func (o *roughYaml) ToYaml() (string, error) {
+func (o *RoughYaml) ToYaml() (string, error) {
 	bytes, err := yaml.Marshal(o.GetContents())
 	if err != nil {
 		return "", err
@@ -169,7 +199,11 @@ func (o *roughYaml) ToYaml() (string, error) {
 	return string(bytes), nil
 }
 
-func (o *roughYaml) GetContents() interface{} {
+func (o *RoughYaml) GetContents() interface{} {
+	if o.isRoot() {
+		// every copy of the root node sees the contents which the document holds now
+		return o.currentItem.Value
+	}
 	if o.contents == nil {
 		return nil
 	}
@@ -191,14 +225,14 @@ func getContents(value interface{}) interface{} {
 	return value
 }
 
-func (o *roughYaml) Key() interface{} {
+func (o *RoughYaml) Key() interface{} {
 	if o.currentItem != nil {
 		return o.currentItem.Key
 	}
 	return nil
 }
 
-func (o *roughYaml) Value() interface{} {
+func (o *RoughYaml) Value() interface{} {
 	if o.currentItem != nil {
 		return o.currentItem.Value
 	}
@@ -208,74 +242,246 @@ func (o *roughYaml) Value() interface{} {
 	return nil
 }
 
-func (o *roughYaml) Get(key string) *roughYaml {
+func (o *RoughYaml) Get(key string) *RoughYaml {
+	return o.get(key)
+}
+
+// GetKey returns the child node for key.
+// Unlike Get, key can be a mapping key of another type than string, such as 404 or true, or an int sequence index.
+func (o *RoughYaml) GetKey(key interface{}) *RoughYaml {
+	return o.get(key)
+}
+
+func (o *RoughYaml) get(key interface{}) *RoughYaml {
+	if o.err != nil {
+		return o.createChildNil(key, o.err)
+	}
 	contents := o.GetContents()
 	if contents == nil {
-		return createRoughYamlNil()
+		return o.createChildNil(key, fmt.Errorf("key '%v' not found under %v", keyString(key), describePath(o.path)))
 	}
 	mapSlice, ok := contents.(*yaml.MapSlice)
 	if ok {
-		for index := range *mapSlice {
+		index := findKeyIndex(*mapSlice, key)
+		if index >= 0 {
 			referencedItem := &(*mapSlice)[index]
-			if referencedItem.Key == key {
-				if referencedItem.Value == nil {
-					return createRoughYaml(nil, referencedItem)
-				}
-				mapSlicePointer, ok := referencedItem.Value.(*yaml.MapSlice)
-				if ok {
-					return createRoughYaml(mapSlicePointer, referencedItem)
-				}
-				mapSliceValue, ok := referencedItem.Value.(yaml.MapSlice)
-				if ok {
-					return createRoughYaml(&mapSliceValue, referencedItem)
-				}
-				return createRoughYaml(&referencedItem.Value, referencedItem)
+			// the key of the mapping, which can differ in type from the key asked for, such as 404 for "404"
+			key = referencedItem.Key
+			if referencedItem.Value == nil {
+				return o.createChild(key, nil, referencedItem)
 			}
+			mapSlicePointer, ok := referencedItem.Value.(*yaml.MapSlice)
+			if ok {
+				return o.createChild(key, mapSlicePointer, referencedItem)
+			}
+			mapSliceValue, ok := referencedItem.Value.(yaml.MapSlice)
+			if ok {
+				return o.createChild(key, &mapSliceValue, referencedItem)
+			}
+			return o.createChild(key, &referencedItem.Value, referencedItem)
 		}
+		return o.createChildNil(key, fmt.Errorf("key '%v' not found under %v", keyString(key), describePath(o.path)))
 	}
 	slice, ok := contents.(*interface{})
-	if ok {
+	if ok && *slice != nil {
 		// > go - range over interface{} which stores a slice - Stack Overflow
 		// > https://stackoverflow.com/questions/14025833/range-over-interface-which-stores-a-slice?answertab=active#tab-top
 		switch reflect.TypeOf(*slice).Kind() {
 		case reflect.Slice:
 			s := reflect.ValueOf(*slice)
-			for i := 0; i < s.Len(); i++ {
-				index := strconv.FormatInt(int64(i), 10)
-				if index == key {
-					interfaceValue := s.Index(i).Interface()
-					mapSlicePointer, ok := interfaceValue.(*yaml.MapSlice)
-					if ok {
-						v := yaml.MapItem{Key: nil, Value: mapSlicePointer}
-						return createRoughYaml(mapSlicePointer, &v)
-					}
-					mapSliceValue, ok := interfaceValue.(yaml.MapSlice)
-					if ok {
-						v := yaml.MapItem{Key: nil, Value: mapSliceValue}
-						return createRoughYaml(&mapSliceValue, &v)
-					}
-					v := yaml.MapItem{Key: nil, Value: interfaceValue}
-					return createRoughYaml(&v.Value, &v)
-				}
+			i, isIndex := sequenceIndex(key)
+			if !isIndex {
+				return o.createChildNil(key, fmt.Errorf("key '%v' is not an index of the sequence %v", keyString(key), describePath(o.path)))
+			}
+			if i < 0 || i >= s.Len() {
+				return o.createChildNil(key, fmt.Errorf("index %v out of range (len %v) under %v", i, s.Len(), describePath(o.path)))
+			}
+			key = i
+			interfaceValue := s.Index(i).Interface()
+			var slot *interface{}
+			if elements, ok := (*slice).([]interface{}); ok {
+				slot = &elements[i]
+			}
+			mapSlicePointer, ok := interfaceValue.(*yaml.MapSlice)
+			if ok {
+				v := yaml.MapItem{Key: nil, Value: mapSlicePointer}
+				return o.createElement(key, mapSlicePointer, &v, slot)
+			}
+			mapSliceValue, ok := interfaceValue.(yaml.MapSlice)
+			if ok {
+				v := yaml.MapItem{Key: nil, Value: mapSliceValue}
+				return o.createElement(key, &mapSliceValue, &v, slot)
+			}
+			v := yaml.MapItem{Key: nil, Value: interfaceValue}
+			if interfaceValue == nil {
+				return o.createElement(key, nil, &v, slot)
 			}
+			return o.createElement(key, &v.Value, &v, slot)
 		}
 	}
-	return createRoughYamlNil()
+	return o.createChildNil(key, fmt.Errorf("key '%v' not found: %v is not a mapping or a sequence", keyString(key), describePath(o.path)))
+}
+
+// GetPath returns the node reached by calling Get with each key in turn.
+func (o *RoughYaml) GetPath(keys ...string) *RoughYaml {
+	node := o
+	for _, key := range keys {
+		node = node.Get(key)
+	}
+	return node
+}
+
+// findKeyIndex returns the index of key in mapSlice, or -1 if it is not found.
+// A string key which is not found as it is matches a key of another type with the same string form, so that "404" finds 404.
+func findKeyIndex(mapSlice yaml.MapSlice, key interface{}) int {
+	for index := range mapSlice {
+		if keysEqual(mapSlice[index].Key, key) {
+			return index
+		}
+	}
+	if name, ok := key.(string); ok {
+		for index := range mapSlice {
+			if _, isString := mapSlice[index].Key.(string); !isString && keyString(mapSlice[index].Key) == name {
+				return index
+			}
+		}
+	}
+	return -1
+}
+
+func keysEqual(a interface{}, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if reflect.TypeOf(a) != reflect.TypeOf(b) || !reflect.TypeOf(a).Comparable() {
+		return false
+	}
+	return a == b
+}
+
+// sequenceIndex returns key as a sequence index, accepting an integer or its decimal string form.
+func sequenceIndex(key interface{}) (int, bool) {
+	if name, ok := key.(string); ok {
+		index, err := strconv.Atoi(name)
+		return index, err == nil && strconv.Itoa(index) == name
+	}
+	value := reflect.ValueOf(key)
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(value.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(value.Uint()), true
+	}
+	return 0, false
+}
+
+// createChild returns the node for key under o.
+func (o *RoughYaml) createChild(key interface{}, yamlContents interface{}, item *yaml.MapItem) *RoughYaml {
+	child := createRoughYaml(yamlContents, item)
+	child.path = appendPath(o.path, keyString(key))
+	child.keys = append(append(make([]interface{}, 0, len(o.keys)+1), o.keys...), key)
+	child.doc = o.doc
+	return child
+}
+
+// createElement returns the node for a sequence entry under o, which writes its value through to slot.
+func (o *RoughYaml) createElement(key interface{}, yamlContents interface{}, item *yaml.MapItem, slot *interface{}) *RoughYaml {
+	child := o.createChild(key, yamlContents, item)
+	child.slot = slot
+	return child
+}
+
+// createChildNil returns the node for key under o, which is missing because of err.
+func (o *RoughYaml) createChildNil(key interface{}, err error) *RoughYaml {
+	child := createRoughYamlNil()
+	child.path = appendPath(o.path, keyString(key))
+	child.err = err
+	child.doc = o.doc
+	return child
+}
+
+// Err returns the first failure in the chain of Get calls which returned the node, or nil if the node exists.
+func (o *RoughYaml) Err() error {
+	return o.err
+}
+
+// Path returns the keys from the root to the node, sequence indexes as strings.
+func (o *RoughYaml) Path() []string {
+	return append([]string{}, o.path...)
+}
+
+func describePath(path []string) string {
+	if len(path) == 0 {
+		return "the root"
+	}
+	return "'" + strings.Join(path, ".") + "'"
+}
+
+// keyString returns the canonical string form of a key, such as "404" for 404 and "null" for nil.
+func keyString(key interface{}) string {
+	switch k := key.(type) {
+	case string:
+		return k
+	case nil:
+		return "null"
+	}
+	return fmt.Sprint(key)
 }
 
-func (o *roughYaml) Set(key string, value interface{}) {
-	o.setValue(key, value, false)
+func appendPath(path []string, key string) []string {
+	newPath := make([]string, len(path), len(path)+1)
+	copy(newPath, path)
+	return append(newPath, key)
 }
 
-func (o *roughYaml) SetForce(key string, value interface{}) {
-	o.setValue(key, value, true)
+// Lookup returns the child node for key as a read-only Node.
+func (o *RoughYaml) Lookup(key string) Node {
+	return o.Get(key)
+}
+
+// Set replaces the value of key. It returns an error if the key does not exist.
+func (o *RoughYaml) Set(key string, value interface{}) error {
+	return o.setValue(key, value, false)
+}
+
+// SetForce replaces the value of key, adding the key at the end of the mapping if it does not exist.
+func (o *RoughYaml) SetForce(key string, value interface{}) error {
+	return o.setValue(key, value, true)
+}
+
+// SetKey is Set for a key of any type, such as 404 or true.
+func (o *RoughYaml) SetKey(key interface{}, value interface{}) error {
+	return o.setValue(key, value, false)
+}
+
+// SetForceKey is SetForce for a key of any type, such as 404 or true.
+func (o *RoughYaml) SetForceKey(key interface{}, value interface{}) error {
+	return o.setValue(key, value, true)
+}
+
+func (o *RoughYaml) setValue(key interface{}, value interface{}, isForce bool) error {
+	if o.err != nil {
+		return o.err
+	}
+	change := Change{Operation: OperationReplace, Path: appendPath(o.path, keyString(key)), NewValue: value}
+	if child := o.get(key); child.currentItem != nil {
+		change.OldValue = child.Value()
+	} else {
+		change.Operation = OperationAdd
+	}
+	return o.recordEdit(false, change, []interface{}{key}, func() error {
+		return o.setValueNow(key, value, isForce)
+	})
 }
 
-func (o *roughYaml) setValue(key string, value interface{}, isForce bool) {
-	childMapSlice := o.Get(key)
+func (o *RoughYaml) setValueNow(key interface{}, value interface{}, isForce bool) error {
+	if o.currentItem == nil {
+		return ErrDetachedNode
+	}
+	childMapSlice := o.get(key)
 	if childMapSlice.currentItem == nil {
 		if !isForce {
-			return
+			return childMapSlice.err
 		}
 		newMapSlice := yaml.MapSlice{}
 		newMapItem := yaml.MapItem{
@@ -296,44 +502,71 @@ func (o *roughYaml) setValue(key string, value interface{}, isForce bool) {
 		}
 		newMapSlice = append(newMapSlice, newMapItem)
 		setContentsValue(o, &newMapSlice)
-		childMapSlice = o.Get(key)
+		childMapSlice = o.get(key)
 	}
 
 	setContentsValue(childMapSlice, value)
+	return nil
 }
 
-func setContentsValue(o *roughYaml, value interface{}) {
+func setContentsValue(o *RoughYaml, value interface{}) {
 	if o.currentItem == nil {
 		return
 	}
 	o.contents = value
 	o.currentItem.Value = value
+	if o.slot != nil {
+		*o.slot = value
+	}
 }
 
-func (o *roughYaml) Delete(key string) {
-	if o.contents == nil {
-		return
+// Delete removes key. It returns an error if the key does not exist.
+func (o *RoughYaml) Delete(key string) error {
+	return o.deleteKey(key)
+}
+
+// DeleteKey is Delete for a key of any type, such as 404 or true.
+func (o *RoughYaml) DeleteKey(key interface{}) error {
+	return o.deleteKey(key)
+}
+
+func (o *RoughYaml) deleteKey(key interface{}) error {
+	if o.err != nil {
+		return o.err
+	}
+	if o.currentItem == nil {
+		return ErrDetachedNode
+	}
+	child := o.get(key)
+	if child.currentItem == nil {
+		return child.err
 	}
 	mapSlice, ok := o.GetContents().(*yaml.MapSlice)
-	newMapSlice := yaml.MapSlice{}
-	if ok {
+	if !ok {
+		return fmt.Errorf("cannot delete key '%v': %v is not a mapping", keyString(key), describePath(o.path))
+	}
+	change := Change{Operation: OperationDelete, Path: appendPath(o.path, keyString(key)), OldValue: child.Value()}
+	return o.recordEdit(false, change, []interface{}{key}, func() error {
+		deleteIndex := findKeyIndex(*mapSlice, key)
+		newMapSlice := yaml.MapSlice{}
 		for index := range *mapSlice {
 			referencedItem := &(*mapSlice)[index]
-			if referencedItem.Key != key {
+			if index != deleteIndex {
 				newMapSlice = append(newMapSlice, *referencedItem)
 			}
 		}
-	}
 
-	if len(newMapSlice) == 0 {
-		setContentsValue(o, nil)
-		return
-	}
+		if len(newMapSlice) == 0 {
+			setContentsValue(o, nil)
+			return nil
+		}
 
-	setContentsValue(o, newMapSlice)
+		setContentsValue(o, &newMapSlice)
+		return nil
+	})
 }
 
-func (o *roughYaml) HasNext() bool {
+func (o *RoughYaml) HasNext() bool {
 	if !o.isListCurrentItem {
 		return false
 	}
@@ -343,9 +576,10 @@ func (o *roughYaml) HasNext() bool {
 	return true
 }
 
-func (o *roughYaml) Next() *roughYaml {
+func (o *RoughYaml) Next() *RoughYaml {
 	if o.currentIndex+1 >= o.liseSizeCurrentItem {
-		return createRoughYamlNil()
+		index := o.currentIndex + 1
+		return o.createChildNil(index, fmt.Errorf("index %v out of range (len %v) under %v", index, o.liseSizeCurrentItem, describePath(o.path)))
 	}
 	o.currentIndex++
 	index := strconv.Itoa(o.currentIndex)
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"gopkg.in/yaml.v2"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -194,6 +195,154 @@ aaa:
 	t.Logf("actualKey:%v, expectedKey:%v | actualValue:%v, expectedValue:%v\n", actualKey, expectedKey, actualValue, expectedValue)
 }
 
+func TestLookup(t *testing.T) {
+	//---------------------
+	// init
+	yamlString := `
+aaa:
+  bbb:
+    bbb1: bbb
+  ccc:
+    - 1
+`
+	var expectedValue interface{}
+	var actualValue interface{}
+
+	roughYamlObj := FromYaml(yamlString)
+	var node Node = &roughYamlObj
+
+	//
+	//
+	//---------------------
+	// success (value)
+	expectedValue = "bbb"
+	actualValue = node.Lookup("aaa").Lookup("bbb").Lookup("bbb1").Value()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+	}
+	t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+
+	//
+	//
+	//---------------------
+	// success (slice)
+	expectedValue = 1
+	actualValue = node.Lookup("aaa").Lookup("ccc").Lookup("0").Value()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+	}
+	t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+
+	//
+	//
+	//---------------------
+	// success (nil)
+	expectedValue = nil
+	actualValue = node.Lookup("xxx").Lookup("yyy").Value()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+	}
+	t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+}
+
+func TestGetKey(t *testing.T) {
+	//---------------------
+	// init
+	yamlString := `
+aaa:
+  404: not-found
+  true: "yes"
+  1.5: one-and-a-half
+  "200": ok
+  200: int-ok
+  ccc:
+  - 1
+  - 2
+`
+	var expectedValue interface{}
+	var actualValue interface{}
+
+	roughYamlObj := FromYaml(yamlString)
+
+	//
+	//
+	//---------------------
+	// success (typed key)
+	expectedValue = "not-found"
+	actualValue = roughYamlObj.Get("aaa").GetKey(404).Value()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+	}
+	t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	expectedValue = "int-ok"
+	actualValue = roughYamlObj.Get("aaa").GetKey(200).Value()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+	}
+	t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	expectedValue = 2
+	actualValue = roughYamlObj.Get("aaa").Get("ccc").GetKey(1).Value()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+	}
+	t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+
+	//
+	//
+	//---------------------
+	// success (canonical string key)
+	expectedValue = "yes"
+	actualValue = roughYamlObj.Get("aaa").Get("true").Value()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+	}
+	t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	expectedValue = "one-and-a-half"
+	actualValue = roughYamlObj.Get("aaa").Get("1.5").Value()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+	}
+	t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	expectedValue = "ok"
+	actualValue = roughYamlObj.Get("aaa").Get("200").Value()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+	}
+	t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+
+	//
+	//
+	//---------------------
+	// success (set and delete)
+	roughYamlObj.Get("aaa").Set("404", "gone")
+	roughYamlObj.Get("aaa").SetKey(true, "no")
+	roughYamlObj.Get("aaa").SetForceKey(500, "error")
+	roughYamlObj.Get("aaa").DeleteKey(200)
+	roughYamlObj.Get("aaa").Delete("1.5")
+	expectedValue = `aaa:
+  404: gone
+  true: "no"
+  "200": ok
+  ccc:
+  - 1
+  - 2
+  500: error
+`
+	actualValue, _ = roughYamlObj.ToYaml()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+
+	//
+	//
+	//---------------------
+	// error (not found)
+	if roughYamlObj.Get("aaa").GetKey(405).Err() == nil || roughYamlObj.Get("aaa").DeleteKey(false) == nil {
+		t.Errorf("<< FAILED >>>")
+	}
+}
+
 func TestSet(t *testing.T) {
 	//---------------------
 	// init
@@ -541,6 +690,39 @@ aaa:
 	}
 }
 
+func TestSetSliceValue(t *testing.T) {
+	//---------------------
+	// init
+	yamlString := `
+aaa:
+  ccc:
+  - 1
+  - ddd: ddd1
+`
+	var expectedValue interface{}
+	var actualValue interface{}
+
+	roughYamlObj := FromYaml(yamlString)
+
+	//
+	//
+	//---------------------
+	// success (set slice value and add a key to a mapping in a slice)
+	roughYamlObj.Get("aaa").Get("ccc").Set("0", 5)
+	roughYamlObj.Get("aaa").Get("ccc").Get("1").SetForce("eee", "eee1")
+	expectedValue = `aaa:
+  ccc:
+  - 5
+  - ddd: ddd1
+    eee: eee1
+`
+	actualValue, _ = roughYamlObj.ToYaml()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+}
+
 func TestDelete(t *testing.T) {
 	//---------------------
 	// init
@@ -613,6 +795,102 @@ aaa:
 	}
 }
 
+func TestErr(t *testing.T) {
+	//---------------------
+	// init
+	yamlString := `
+aaa:
+  bbb:
+    bbb1: bbb
+  ccc:
+  - 1
+  - 2
+  - 3
+`
+	var expectedValue interface{}
+	var actualValue interface{}
+
+	roughYamlObj := FromYaml(yamlString)
+
+	//
+	//
+	//---------------------
+	// success (no error)
+	actualValue = roughYamlObj.Get("aaa").Get("bbb").Get("bbb1").Err()
+	if actualValue != nil {
+		t.Errorf("<< FAILED >>>")
+	}
+	t.Logf("actualValue:%v\n", actualValue)
+
+	//
+	//
+	//---------------------
+	// success (missing key is the first failure)
+	expectedValue = "key 'xxx' not found under 'aaa'"
+	actualValue = roughYamlObj.Get("aaa").Get("xxx").Get("yyy").Get("zzz").Err().Error()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+	}
+	t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	expectedValue = "aaa.xxx.yyy.zzz"
+	actualValue = strings.Join(roughYamlObj.Get("aaa").Get("xxx").Get("yyy").Get("zzz").Path(), ".")
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+	}
+	t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+
+	//
+	//
+	//---------------------
+	// success (index out of range)
+	expectedValue = "index 5 out of range (len 3) under 'aaa.ccc'"
+	actualValue = roughYamlObj.Get("aaa").Get("ccc").Get("5").Err().Error()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+	}
+	t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+
+	//
+	//
+	//---------------------
+	// success (mutations on a failed node report the failure)
+	expectedValue = "key 'xxx' not found under the root"
+	for _, err := range []error{
+		roughYamlObj.Get("xxx").Get("yyy").Set("zzz", 1),
+		roughYamlObj.Get("xxx").Get("yyy").SetForce("zzz", 1),
+		roughYamlObj.Get("xxx").Get("yyy").Delete("zzz"),
+	} {
+		if err == nil || err.Error() != expectedValue {
+			t.Errorf("<< FAILED >>> : %v", err)
+		}
+	}
+	expectedValue = "key 'xxx' not found under 'aaa.bbb'"
+	actualValue = roughYamlObj.Get("aaa").Get("bbb").Set("xxx", 1).Error()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+	}
+	t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+
+	//
+	//
+	//---------------------
+	// success (next past the end reports the failure)
+	list := roughYamlObj.Get("aaa").Get("ccc")
+	for list.HasNext() {
+		list.Next()
+	}
+	expectedValue = "index 3 out of range (len 3) under 'aaa.ccc'"
+	for _, err := range []error{list.Next().Err(), list.Next().SetForce("xxx", 1)} {
+		if err == nil || err.Error() != expectedValue {
+			t.Errorf("<< FAILED >>> : %v", err)
+		}
+	}
+	var detached RoughYaml
+	if err := detached.SetForce("xxx", 1); err != ErrDetachedNode {
+		t.Errorf("<< FAILED >>> : %v", err)
+	}
+}
+
 func compareSlice(a []interface{}, b []interface{}) bool {
 	if len(a) != len(b) {
 		return false
@@ -713,7 +991,7 @@ func toSlice(value interface{}) []interface{} {
 	return slice
 }
 
-func printYaml(roughYamlObj roughYaml) {
+func printYaml(roughYamlObj RoughYaml) {
 	yamlString, err := roughYamlObj.ToYaml()
 	if err != nil {
 		fmt.Println(err)
@@ -0,0 +1,295 @@
+package goroughyaml
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Rule checks the values at a path of a document. Rules are built with Require, Optional and ForEach
+// and checked by Validate.
+//
+// A path is a dotted list of keys in which sequence indexes can be written as [0], and "*" or [*]
+// stands for every element of a sequence or every value of a mapping, such as "servers[*].port".
+// The checks of a rule run in order and stop at the first failure for each value,
+// so that Require("port").IsInt().Min(1) reports a string as not an integer only.
+type Rule struct {
+	path     []string
+	required bool
+	checks   []ruleCheck
+	rules    []*Rule
+}
+
+type ruleCheck struct {
+	keyword string
+	check   func(value interface{}) string
+}
+
+// Require returns a rule for path, which fails where the value does not exist.
+func Require(path string) *Rule {
+	return &Rule{path: parseRulePath(path), required: true}
+}
+
+// Optional returns a rule for path, whose checks run only where the value exists.
+func Optional(path string) *Rule {
+	return &Rule{path: parseRulePath(path)}
+}
+
+// ForEach returns a rule which checks rules against each element of the sequence, or each value of the mapping, at path.
+// The paths of rules are relative to the element, and "" is the element itself.
+// A missing value at path is not a failure; combine ForEach with Require for that.
+func ForEach(path string, rules ...*Rule) *Rule {
+	parsed := parseRulePath(path)
+	if len(parsed) == 0 || parsed[len(parsed)-1] != "*" {
+		parsed = append(parsed, "*")
+	}
+	return &Rule{path: parsed, rules: rules}
+}
+
+func parseRulePath(path string) []string {
+	path = strings.Replace(strings.Replace(path, "[", ".", -1), "]", "", -1)
+	var keys []string
+	for _, key := range strings.Split(path, ".") {
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// Check adds a check named keyword, which fails with the message returned by check unless it is "".
+func (r *Rule) Check(keyword string, check func(value interface{}) string) *Rule {
+	r.checks = append(r.checks, ruleCheck{keyword: keyword, check: check})
+	return r
+}
+
+// IsString checks that the value is a string.
+func (r *Rule) IsString() *Rule {
+	return r.Check("string", func(value interface{}) string {
+		if _, ok := value.(string); !ok {
+			return "must be a string, not " + schemaType(value)
+		}
+		return ""
+	})
+}
+
+// IsInt checks that the value is an integer.
+func (r *Rule) IsInt() *Rule {
+	return r.Check("int", func(value interface{}) string {
+		switch reflect.ValueOf(value).Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return ""
+		}
+		return "must be an integer, not " + schemaType(value)
+	})
+}
+
+// IsNumber checks that the value is an integer or a floating point number.
+func (r *Rule) IsNumber() *Rule {
+	return r.Check("number", func(value interface{}) string {
+		if _, ok := numberValue(value, false); !ok {
+			return "must be a number, not " + schemaType(value)
+		}
+		return ""
+	})
+}
+
+// IsBool checks that the value is true or false.
+func (r *Rule) IsBool() *Rule {
+	return r.Check("bool", func(value interface{}) string {
+		if _, ok := value.(bool); !ok {
+			return "must be a boolean, not " + schemaType(value)
+		}
+		return ""
+	})
+}
+
+// IsList checks that the value is a sequence.
+func (r *Rule) IsList() *Rule {
+	return r.Check("list", func(value interface{}) string {
+		if _, ok := value.([]interface{}); !ok {
+			return "must be a list, not " + schemaType(value)
+		}
+		return ""
+	})
+}
+
+// IsMap checks that the value is a mapping.
+func (r *Rule) IsMap() *Rule {
+	return r.Check("map", func(value interface{}) string {
+		if _, ok := value.(yaml.MapSlice); !ok {
+			return "must be a mapping, not " + schemaType(value)
+		}
+		return ""
+	})
+}
+
+// Min checks that the value is a number greater than or equal to min.
+func (r *Rule) Min(min float64) *Rule {
+	return r.Check("min", func(value interface{}) string {
+		if number, ok := numberValue(value, false); !ok || number < min {
+			return fmt.Sprintf("must be a number >= %v", min)
+		}
+		return ""
+	})
+}
+
+// Max checks that the value is a number less than or equal to max.
+func (r *Rule) Max(max float64) *Rule {
+	return r.Check("max", func(value interface{}) string {
+		if number, ok := numberValue(value, false); !ok || number > max {
+			return fmt.Sprintf("must be a number <= %v", max)
+		}
+		return ""
+	})
+}
+
+// MinLength checks that the value is a string with at least min characters, or a sequence or a mapping with at least min entries.
+func (r *Rule) MinLength(min int) *Rule {
+	return r.Check("minLength", func(value interface{}) string {
+		if length, ok := valueLength(value); !ok || length < min {
+			return fmt.Sprintf("must have a length >= %v", min)
+		}
+		return ""
+	})
+}
+
+// MaxLength checks that the value is a string with at most max characters, or a sequence or a mapping with at most max entries.
+func (r *Rule) MaxLength(max int) *Rule {
+	return r.Check("maxLength", func(value interface{}) string {
+		if length, ok := valueLength(value); !ok || length > max {
+			return fmt.Sprintf("must have a length <= %v", max)
+		}
+		return ""
+	})
+}
+
+// Matches checks that the value is a string which matches the regular expression pattern.
+// It panics if pattern is not a valid regular expression.
+func (r *Rule) Matches(pattern string) *Rule {
+	compiled := regexp.MustCompile(pattern)
+	return r.Check("matches", func(value interface{}) string {
+		if s, ok := value.(string); !ok || !compiled.MatchString(s) {
+			return "must match the pattern " + pattern
+		}
+		return ""
+	})
+}
+
+// OneOf checks that the value equals one of values. Numbers are compared by value, so 1 equals 1.0.
+func (r *Rule) OneOf(values ...interface{}) *Rule {
+	return r.Check("oneOf", func(value interface{}) string {
+		for _, allowed := range values {
+			if jsonEqual(allowed, value) {
+				return ""
+			}
+		}
+		return "must be one of " + jsonText(values)
+	})
+}
+
+func valueLength(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case string:
+		return utf8.RuneCountInString(v), true
+	case []interface{}:
+		return len(v), true
+	case yaml.MapSlice:
+		return len(v), true
+	}
+	return 0, false
+}
+
+// Validate checks rules against node and returns a ValidationErrors with all the failures, or nil.
+func Validate(node *RoughYaml, rules ...*Rule) error {
+	if node.err != nil {
+		return node.err
+	}
+	var errs ValidationErrors
+	for _, rule := range rules {
+		errs = append(errs, rule.validate(plainValue(node.GetContents()), node.Path())...)
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return withLines(errs, node)
+}
+
+func (r *Rule) validate(root interface{}, base []string) ValidationErrors {
+	var errs ValidationErrors
+	for _, match := range matchRulePath(root, r.path, base) {
+		if !match.exists {
+			if r.required {
+				errs = append(errs, ValidationError{Path: match.path, Keyword: "required", Message: "is required"})
+			}
+			continue
+		}
+		for _, c := range r.checks {
+			if message := c.check(match.value); message != "" {
+				errs = append(errs, ValidationError{Path: match.path, Keyword: c.keyword, Message: message})
+				break
+			}
+		}
+		for _, rule := range r.rules {
+			errs = append(errs, rule.validate(match.value, match.path)...)
+		}
+	}
+	return errs
+}
+
+// ruleMatch is a value which a rule path stands for.
+type ruleMatch struct {
+	path   []string
+	value  interface{}
+	exists bool
+}
+
+// matchRulePath returns the values at path below value, which is at base in the document.
+// A missing key is a match which does not exist, while "*" matches only the existing entries.
+func matchRulePath(value interface{}, path []string, base []string) []ruleMatch {
+	if len(path) == 0 {
+		return []ruleMatch{{path: base, value: value, exists: true}}
+	}
+	key := path[0]
+	var matches []ruleMatch
+	switch v := value.(type) {
+	case yaml.MapSlice:
+		if key != "*" {
+			if index := findKeyIndex(v, key); index >= 0 {
+				return matchRulePath(plainValue(v[index].Value), path[1:], appendPath(base, keyString(v[index].Key)))
+			}
+			break
+		}
+		for _, item := range v {
+			matches = append(matches, matchRulePath(plainValue(item.Value), path[1:], appendPath(base, keyString(item.Key)))...)
+		}
+		return matches
+	case []interface{}:
+		if key != "*" {
+			if index, ok := sequenceIndex(key); ok && index >= 0 && index < len(v) {
+				return matchRulePath(plainValue(v[index]), path[1:], appendPath(base, key))
+			}
+			break
+		}
+		for index, element := range v {
+			matches = append(matches, matchRulePath(plainValue(element), path[1:], appendPath(base, strconv.Itoa(index)))...)
+		}
+		return matches
+	}
+	if key == "*" {
+		return nil
+	}
+	missing := base
+	for _, rest := range path {
+		if rest == "*" {
+			break
+		}
+		missing = appendPath(missing, rest)
+	}
+	return []ruleMatch{{path: missing}}
+}
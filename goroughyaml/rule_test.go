@@ -0,0 +1,71 @@
+package goroughyaml
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidateRules(t *testing.T) {
+	//---------------------
+	// init
+	yamlString := `
+server:
+  port: "8080"
+  mode: test
+items:
+- name: aaa
+  size: 1
+- size: 0
+- name: ccc
+  size: 2
+labels:
+  app: web
+  tier: 3
+`
+	var expectedValue interface{}
+	var actualValue interface{}
+
+	roughYamlObj := FromYaml(yamlString)
+
+	//
+	//
+	//---------------------
+	// error (all violations)
+	err := Validate(&roughYamlObj,
+		Require("server.port").IsInt().Min(1),
+		Require("server.host").IsString(),
+		Optional("server.mode").OneOf("dev", "prod"),
+		Optional("server.timeout").IsInt(),
+		ForEach("items[*]",
+			Require("name").IsString().MinLength(1),
+			Require("size").IsInt().Min(1),
+		),
+		Require("items[2].name").Matches("^c+$"),
+		ForEach("labels", Require("").IsString()),
+	)
+	expectedValue = ValidationErrors{
+		{Path: []string{"server", "port"}, Line: 3, Keyword: "int", Message: "must be an integer, not string"},
+		{Path: []string{"server", "host"}, Line: 2, Keyword: "required", Message: "is required"},
+		{Path: []string{"server", "mode"}, Line: 4, Keyword: "oneOf", Message: `must be one of ["dev","prod"]`},
+		{Path: []string{"items", "1", "name"}, Line: 8, Keyword: "required", Message: "is required"},
+		{Path: []string{"items", "1", "size"}, Line: 8, Keyword: "min", Message: "must be a number >= 1"},
+		{Path: []string{"labels", "tier"}, Line: 13, Keyword: "string", Message: "must be a string, not integer"},
+	}
+	actualValue = err
+	if !reflect.DeepEqual(actualValue, expectedValue) {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v\nexpectedValue:%v\n", actualValue, expectedValue)
+	}
+
+	//
+	//
+	//---------------------
+	// success
+	err = Validate(roughYamlObj.Get("items"),
+		ForEach("", Require("size").IsNumber().Max(2)),
+		Require("[0].name").OneOf("aaa"),
+	)
+	if err != nil {
+		t.Errorf("<< FAILED >>> : %v", err)
+	}
+}
@@ -0,0 +1,551 @@
+package goroughyaml
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Schema is a JSON Schema (draft 2020-12) which documents can be validated against.
+//
+// The supported keywords are
+// type, enum, const, $ref, $defs (and definitions), allOf, anyOf, oneOf, not, if, then, else,
+// properties, patternProperties, additionalProperties, propertyNames, required, dependentRequired,
+// minProperties, maxProperties, prefixItems, items, contains, minContains, maxContains, minItems, maxItems, uniqueItems,
+// minimum, maximum, exclusiveMinimum, exclusiveMaximum, multipleOf, minLength, maxLength and pattern.
+// Other keywords, such as format, are ignored. $ref must point into the same schema, as "#/$defs/port" does.
+// Patterns use the syntax of the regexp package.
+type Schema struct {
+	root     interface{}
+	patterns map[string]*regexp.Regexp
+}
+
+// ValidationError is a node of a document which does not satisfy a schema or a rule.
+type ValidationError struct {
+	// Path is the path of the node. For a missing key, it is the path the key would have.
+	Path []string
+	// Line is the line of the node, or of its nearest ancestor with a known line, or 0. See RoughYaml.Line.
+	Line int
+	// Keyword is the schema keyword or the rule which failed, such as "type" or "required".
+	Keyword string
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%v (line %v): %v", describePath(e.Path), e.Line, e.Message)
+	}
+	return fmt.Sprintf("%v: %v", describePath(e.Path), e.Message)
+}
+
+// ValidationErrors is the list of the errors of a validation.
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for index, err := range e {
+		messages[index] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// LoadSchema parses a schema written in yaml or json.
+func LoadSchema(content string) (*Schema, error) {
+	var root interface{}
+	mapSlice := yaml.MapSlice{}
+	if err := yaml.Unmarshal([]byte(content), &mapSlice); err == nil {
+		root = mapSlice
+	} else if err := yaml.Unmarshal([]byte(content), &root); err != nil {
+		return nil, err
+	}
+	if _, ok := root.(bool); !ok {
+		if _, ok := root.(yaml.MapSlice); !ok {
+			return nil, fmt.Errorf("schema must be an object or a boolean")
+		}
+	}
+	schema := &Schema{root: root, patterns: map[string]*regexp.Regexp{}}
+	var err error
+	walkSchema(root, func(subschema yaml.MapSlice) {
+		if err != nil {
+			return
+		}
+		if pattern, ok := schemaKeyword(subschema, "pattern"); ok {
+			err = schema.compilePattern(pattern)
+		}
+		if properties, ok := schemaKeyword(subschema, "patternProperties"); ok {
+			for _, item := range plainMapping(properties) {
+				if err == nil {
+					err = schema.compilePattern(keyString(item.Key))
+				}
+			}
+		}
+		if reference, ok := schemaKeyword(subschema, "$ref"); ok && err == nil {
+			if _, ok := schema.resolveReference(reference); !ok {
+				err = fmt.Errorf("cannot resolve $ref %v", reference)
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// LoadSchemaFile reads a schema from the yaml or json file at path.
+func LoadSchemaFile(path string) (*Schema, error) {
+	bytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := LoadSchema(string(bytes))
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", path, err)
+	}
+	return schema, nil
+}
+
+func (s *Schema) compilePattern(pattern interface{}) error {
+	text, ok := pattern.(string)
+	if !ok {
+		return fmt.Errorf("pattern %v is not a string", pattern)
+	}
+	compiled, err := regexp.Compile(text)
+	if err != nil {
+		return err
+	}
+	s.patterns[text] = compiled
+	return nil
+}
+
+// walkSchema calls fn for schema and each of its subschemas which is an object.
+func walkSchema(schema interface{}, fn func(subschema yaml.MapSlice)) {
+	mapSlice, ok := plainValue(schema).(yaml.MapSlice)
+	if !ok {
+		return
+	}
+	fn(mapSlice)
+	for _, item := range mapSlice {
+		switch keyString(item.Key) {
+		case "properties", "patternProperties", "$defs", "definitions", "dependentSchemas":
+			for _, child := range plainMapping(item.Value) {
+				walkSchema(child.Value, fn)
+			}
+		case "items", "additionalProperties", "not", "if", "then", "else", "contains", "propertyNames":
+			walkSchema(item.Value, fn)
+		case "prefixItems", "allOf", "anyOf", "oneOf":
+			if elements, ok := item.Value.([]interface{}); ok {
+				for _, element := range elements {
+					walkSchema(element, fn)
+				}
+			}
+		}
+	}
+}
+
+// schemaKeyword returns the value of keyword in schema.
+func schemaKeyword(schema yaml.MapSlice, keyword string) (interface{}, bool) {
+	index := findKeyIndex(schema, keyword)
+	if index < 0 {
+		return nil, false
+	}
+	return plainValue(schema[index].Value), true
+}
+
+func plainMapping(value interface{}) yaml.MapSlice {
+	mapSlice, _ := plainValue(value).(yaml.MapSlice)
+	return mapSlice
+}
+
+// resolveReference returns the subschema which reference, a JSON Pointer into the schema, points to.
+func (s *Schema) resolveReference(reference interface{}) (interface{}, bool) {
+	text, ok := reference.(string)
+	if !ok || !strings.HasPrefix(text, "#") {
+		return nil, false
+	}
+	current := s.root
+	if text == "#" {
+		return current, true
+	}
+	if !strings.HasPrefix(text, "#/") {
+		return nil, false
+	}
+	for _, token := range strings.Split(text[2:], "/") {
+		token = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+		switch v := plainValue(current).(type) {
+		case yaml.MapSlice:
+			index := findKeyIndex(v, token)
+			if index < 0 {
+				return nil, false
+			}
+			current = v[index].Value
+		case []interface{}:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(v) {
+				return nil, false
+			}
+			current = v[index]
+		default:
+			return nil, false
+		}
+	}
+	return plainValue(current), true
+}
+
+// Validate checks node against the schema and returns a ValidationErrors with all the failures, or nil.
+func (s *Schema) Validate(node *RoughYaml) error {
+	if node.err != nil {
+		return node.err
+	}
+	errs := s.validate(s.root, plainValue(node.GetContents()), node.Path(), 0)
+	if len(errs) == 0 {
+		return nil
+	}
+	return withLines(errs, node)
+}
+
+// ToYaml returns the schema as yaml.
+func (s *Schema) ToYaml() (string, error) {
+	if mapSlice, ok := s.root.(yaml.MapSlice); ok {
+		roughYaml := newRoughYaml(&mapSlice)
+		return roughYaml.ToYaml()
+	}
+	out, err := yaml.Marshal(s.root)
+	return string(out), err
+}
+
+// ToJSON returns the schema as indented json.
+func (s *Schema) ToJSON() ([]byte, error) {
+	compact := &bytes.Buffer{}
+	if err := writeJSON(compact, s.root); err != nil {
+		return nil, err
+	}
+	indented := &bytes.Buffer{}
+	if err := json.Indent(indented, compact.Bytes(), "", "  "); err != nil {
+		return nil, err
+	}
+	indented.WriteString("\n")
+	return indented.Bytes(), nil
+}
+
+// withLines fills in the lines of errs from the document of node.
+func withLines(errs ValidationErrors, node *RoughYaml) ValidationErrors {
+	if node.doc == nil {
+		return errs
+	}
+	for index := range errs {
+		path := errs[index].Path
+		for length := len(path); length >= 0 && errs[index].Line == 0; length-- {
+			errs[index].Line = node.doc.line(path[:length])
+		}
+	}
+	return errs
+}
+
+// maxReferenceDepth bounds the $ref chains which do not descend into the document, such as a schema referring to itself.
+const maxReferenceDepth = 100
+
+func (s *Schema) validate(schema interface{}, value interface{}, path []string, depth int) ValidationErrors {
+	if allowed, ok := schema.(bool); ok {
+		if allowed {
+			return nil
+		}
+		return ValidationErrors{{Path: path, Keyword: "false", Message: "is not allowed"}}
+	}
+	mapSlice, ok := plainValue(schema).(yaml.MapSlice)
+	if !ok {
+		return nil
+	}
+	var errs ValidationErrors
+	fail := func(keyword string, format string, args ...interface{}) {
+		errs = append(errs, ValidationError{Path: path, Keyword: keyword, Message: fmt.Sprintf(format, args...)})
+	}
+	for _, item := range mapSlice {
+		keyword := keyString(item.Key)
+		argument := plainValue(item.Value)
+		switch keyword {
+		case "$ref":
+			if depth >= maxReferenceDepth {
+				fail(keyword, "$ref %v is nested too deeply", argument)
+				continue
+			}
+			referenced, _ := s.resolveReference(argument)
+			errs = append(errs, s.validate(referenced, value, path, depth+1)...)
+		case "type":
+			types := []interface{}{argument}
+			if list, ok := argument.([]interface{}); ok {
+				types = list
+			}
+			matched := false
+			var names []string
+			for _, name := range types {
+				names = append(names, keyString(name))
+				matched = matched || typeMatches(keyString(name), value)
+			}
+			if !matched {
+				fail(keyword, "must be %v, not %v", strings.Join(names, " or "), schemaType(value))
+			}
+		case "enum":
+			list, _ := argument.([]interface{})
+			found := false
+			for _, element := range list {
+				found = found || jsonEqual(element, value)
+			}
+			if !found {
+				fail(keyword, "must be one of %v", jsonText(list))
+			}
+		case "const":
+			if !jsonEqual(argument, value) {
+				fail(keyword, "must be %v", jsonText(argument))
+			}
+		case "allOf":
+			for _, subschema := range schemaList(argument) {
+				errs = append(errs, s.validate(subschema, value, path, depth)...)
+			}
+		case "anyOf":
+			matched := false
+			for _, subschema := range schemaList(argument) {
+				matched = matched || len(s.validate(subschema, value, path, depth)) == 0
+			}
+			if !matched {
+				fail(keyword, "must match at least one schema of anyOf")
+			}
+		case "oneOf":
+			count := 0
+			for _, subschema := range schemaList(argument) {
+				if len(s.validate(subschema, value, path, depth)) == 0 {
+					count++
+				}
+			}
+			if count != 1 {
+				fail(keyword, "must match exactly one schema of oneOf, but matches %v", count)
+			}
+		case "not":
+			if len(s.validate(argument, value, path, depth)) == 0 {
+				fail(keyword, "must not match the schema of not")
+			}
+		case "if":
+			branch := "else"
+			if len(s.validate(argument, value, path, depth)) == 0 {
+				branch = "then"
+			}
+			if subschema, ok := schemaKeyword(mapSlice, branch); ok {
+				errs = append(errs, s.validate(subschema, value, path, depth)...)
+			}
+		}
+	}
+	switch v := value.(type) {
+	case yaml.MapSlice:
+		errs = append(errs, s.validateObject(mapSlice, v, path, depth)...)
+	case []interface{}:
+		errs = append(errs, s.validateArray(mapSlice, v, path, depth)...)
+	case string:
+		length := utf8.RuneCountInString(v)
+		if limit, ok := schemaNumber(mapSlice, "minLength"); ok && float64(length) < limit {
+			fail("minLength", "must be at least %v characters long", limit)
+		}
+		if limit, ok := schemaNumber(mapSlice, "maxLength"); ok && float64(length) > limit {
+			fail("maxLength", "must be at most %v characters long", limit)
+		}
+		if pattern, ok := schemaKeyword(mapSlice, "pattern"); ok {
+			if compiled := s.patterns[keyString(pattern)]; compiled != nil && !compiled.MatchString(v) {
+				fail("pattern", "must match the pattern %v", pattern)
+			}
+		}
+	default:
+		number, ok := numberValue(value, false)
+		if !ok {
+			break
+		}
+		if limit, ok := schemaNumber(mapSlice, "minimum"); ok && number < limit {
+			fail("minimum", "must be >= %v", limit)
+		}
+		if limit, ok := schemaNumber(mapSlice, "maximum"); ok && number > limit {
+			fail("maximum", "must be <= %v", limit)
+		}
+		if limit, ok := schemaNumber(mapSlice, "exclusiveMinimum"); ok && number <= limit {
+			fail("exclusiveMinimum", "must be > %v", limit)
+		}
+		if limit, ok := schemaNumber(mapSlice, "exclusiveMaximum"); ok && number >= limit {
+			fail("exclusiveMaximum", "must be < %v", limit)
+		}
+		if divisor, ok := schemaNumber(mapSlice, "multipleOf"); ok && divisor > 0 {
+			quotient := number / divisor
+			if math.Abs(quotient-math.Round(quotient)) > 1e-9 {
+				fail("multipleOf", "must be a multiple of %v", divisor)
+			}
+		}
+	}
+	return errs
+}
+
+func (s *Schema) validateObject(schema yaml.MapSlice, object yaml.MapSlice, path []string, depth int) ValidationErrors {
+	var errs ValidationErrors
+	properties := plainMapping(lookupKeyword(schema, "properties"))
+	patternProperties := plainMapping(lookupKeyword(schema, "patternProperties"))
+	additional, hasAdditional := schemaKeyword(schema, "additionalProperties")
+	propertyNames, hasPropertyNames := schemaKeyword(schema, "propertyNames")
+	for _, item := range object {
+		name := keyString(item.Key)
+		childPath := appendPath(path, name)
+		value := plainValue(item.Value)
+		evaluated := false
+		if index := findKeyIndex(properties, name); index >= 0 {
+			evaluated = true
+			errs = append(errs, s.validate(properties[index].Value, value, childPath, depth)...)
+		}
+		for _, pattern := range patternProperties {
+			if compiled := s.patterns[keyString(pattern.Key)]; compiled != nil && compiled.MatchString(name) {
+				evaluated = true
+				errs = append(errs, s.validate(pattern.Value, value, childPath, depth)...)
+			}
+		}
+		if !evaluated && hasAdditional {
+			if allowed, ok := additional.(bool); ok && !allowed {
+				errs = append(errs, ValidationError{Path: childPath, Keyword: "additionalProperties", Message: "is not allowed"})
+			} else {
+				errs = append(errs, s.validate(additional, value, childPath, depth)...)
+			}
+		}
+		if hasPropertyNames {
+			if nameErrs := s.validate(propertyNames, name, childPath, depth); len(nameErrs) > 0 {
+				errs = append(errs, ValidationError{Path: childPath, Keyword: "propertyNames", Message: "key " + nameErrs[0].Message})
+			}
+		}
+	}
+	if required, ok := schemaKeyword(schema, "required"); ok {
+		for _, name := range schemaList(required) {
+			if findKeyIndex(object, keyString(name)) < 0 {
+				errs = append(errs, ValidationError{Path: appendPath(path, keyString(name)), Keyword: "required", Message: "is required"})
+			}
+		}
+	}
+	for _, dependency := range plainMapping(lookupKeyword(schema, "dependentRequired")) {
+		if findKeyIndex(object, keyString(dependency.Key)) < 0 {
+			continue
+		}
+		for _, name := range schemaList(dependency.Value) {
+			if findKeyIndex(object, keyString(name)) < 0 {
+				errs = append(errs, ValidationError{Path: appendPath(path, keyString(name)), Keyword: "dependentRequired",
+					Message: fmt.Sprintf("is required when '%v' is present", keyString(dependency.Key))})
+			}
+		}
+	}
+	if limit, ok := schemaNumber(schema, "minProperties"); ok && float64(len(object)) < limit {
+		errs = append(errs, ValidationError{Path: path, Keyword: "minProperties", Message: fmt.Sprintf("must have at least %v keys", limit)})
+	}
+	if limit, ok := schemaNumber(schema, "maxProperties"); ok && float64(len(object)) > limit {
+		errs = append(errs, ValidationError{Path: path, Keyword: "maxProperties", Message: fmt.Sprintf("must have at most %v keys", limit)})
+	}
+	return errs
+}
+
+func (s *Schema) validateArray(schema yaml.MapSlice, array []interface{}, path []string, depth int) ValidationErrors {
+	var errs ValidationErrors
+	prefixItems := schemaList(lookupKeyword(schema, "prefixItems"))
+	items, hasItems := schemaKeyword(schema, "items")
+	for index, element := range array {
+		childPath := appendPath(path, strconv.Itoa(index))
+		if index < len(prefixItems) {
+			errs = append(errs, s.validate(prefixItems[index], plainValue(element), childPath, depth)...)
+		} else if hasItems {
+			errs = append(errs, s.validate(items, plainValue(element), childPath, depth)...)
+		}
+	}
+	if contains, ok := schemaKeyword(schema, "contains"); ok {
+		count := 0
+		for _, element := range array {
+			if len(s.validate(contains, plainValue(element), path, depth)) == 0 {
+				count++
+			}
+		}
+		minimum := 1.0
+		if limit, ok := schemaNumber(schema, "minContains"); ok {
+			minimum = limit
+		}
+		if float64(count) < minimum {
+			errs = append(errs, ValidationError{Path: path, Keyword: "contains", Message: fmt.Sprintf("must contain at least %v items matching the schema of contains", minimum)})
+		}
+		if limit, ok := schemaNumber(schema, "maxContains"); ok && float64(count) > limit {
+			errs = append(errs, ValidationError{Path: path, Keyword: "maxContains", Message: fmt.Sprintf("must contain at most %v items matching the schema of contains", limit)})
+		}
+	}
+	if limit, ok := schemaNumber(schema, "minItems"); ok && float64(len(array)) < limit {
+		errs = append(errs, ValidationError{Path: path, Keyword: "minItems", Message: fmt.Sprintf("must have at least %v items", limit)})
+	}
+	if limit, ok := schemaNumber(schema, "maxItems"); ok && float64(len(array)) > limit {
+		errs = append(errs, ValidationError{Path: path, Keyword: "maxItems", Message: fmt.Sprintf("must have at most %v items", limit)})
+	}
+	if unique, ok := schemaKeyword(schema, "uniqueItems"); ok && unique == true {
+	duplicates:
+		for i := range array {
+			for j := i + 1; j < len(array); j++ {
+				if jsonEqual(array[i], array[j]) {
+					errs = append(errs, ValidationError{Path: path, Keyword: "uniqueItems", Message: fmt.Sprintf("must not have duplicate items (%v and %v)", i, j)})
+					break duplicates
+				}
+			}
+		}
+	}
+	return errs
+}
+
+func lookupKeyword(schema yaml.MapSlice, keyword string) interface{} {
+	value, _ := schemaKeyword(schema, keyword)
+	return value
+}
+
+func schemaList(value interface{}) []interface{} {
+	list, _ := plainValue(value).([]interface{})
+	return list
+}
+
+func schemaNumber(schema yaml.MapSlice, keyword string) (float64, bool) {
+	value, ok := schemaKeyword(schema, keyword)
+	if !ok {
+		return 0, false
+	}
+	return numberValue(value, false)
+}
+
+// schemaType returns the JSON Schema type of a value, where "integer" is a number without a fraction.
+func schemaType(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case yaml.MapSlice:
+		return "object"
+	case []interface{}:
+		return "array"
+	case float32, float64:
+		number, _ := numberValue(v, false)
+		if number == math.Trunc(number) && !math.IsInf(number, 0) {
+			return "integer"
+		}
+		return "number"
+	}
+	if _, ok := numberValue(value, false); ok {
+		return "integer"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func typeMatches(name string, value interface{}) bool {
+	actual := schemaType(value)
+	return actual == name || (name == "number" && actual == "integer")
+}
+
+// jsonEqual compares values as JSON does, so that 1 equals 1.0 and the order of keys does not matter.
+func jsonEqual(a interface{}, b interface{}) bool {
+	return valuesEqual(a, b, EqualOptions{IgnoreKeyOrder: true, Scalars: CompareNumeric})
+}
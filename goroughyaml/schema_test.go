@@ -0,0 +1,191 @@
+package goroughyaml
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSchemaValidate(t *testing.T) {
+	//---------------------
+	// init
+	schemaString := `
+$schema: https://json-schema.org/draft/2020-12/schema
+type: object
+required: [name, server]
+additionalProperties: false
+properties:
+  name:
+    type: string
+    pattern: ^[a-z]+$
+  server:
+    $ref: "#/$defs/server"
+  tags:
+    type: array
+    items: {type: string, minLength: 2}
+    uniqueItems: true
+  mode:
+    enum: [dev, prod]
+$defs:
+  server:
+    type: object
+    required: [host]
+    properties:
+      host: {type: string}
+      port: {type: integer, minimum: 1, maximum: 65535}
+`
+	schema, err := LoadSchema(schemaString)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var expectedValue interface{}
+	var actualValue interface{}
+
+	//
+	//
+	//---------------------
+	// success (valid document)
+	roughYamlObj := FromYaml(`
+name: app
+server:
+  host: localhost
+  port: 8080.0
+tags: [aa, bb]
+mode: prod
+`)
+	if err := schema.Validate(&roughYamlObj); err != nil {
+		t.Errorf("<< FAILED >>> : %v", err)
+	}
+
+	//
+	//
+	//---------------------
+	// error (all failures with paths and lines)
+	roughYamlObj = FromYaml(`
+name: App
+server:
+  port: 70000
+tags:
+- a
+- bb
+- bb
+mode: test
+extra: 1
+`)
+	err = schema.Validate(&roughYamlObj)
+	expectedValue = ValidationErrors{
+		{Path: []string{"name"}, Line: 2, Keyword: "pattern", Message: "must match the pattern ^[a-z]+$"},
+		{Path: []string{"server", "port"}, Line: 4, Keyword: "maximum", Message: "must be <= 65535"},
+		{Path: []string{"server", "host"}, Line: 3, Keyword: "required", Message: "is required"},
+		{Path: []string{"tags", "0"}, Line: 6, Keyword: "minLength", Message: "must be at least 2 characters long"},
+		{Path: []string{"tags"}, Line: 5, Keyword: "uniqueItems", Message: "must not have duplicate items (1 and 2)"},
+		{Path: []string{"mode"}, Line: 9, Keyword: "enum", Message: `must be one of ["dev","prod"]`},
+		{Path: []string{"extra"}, Line: 10, Keyword: "additionalProperties", Message: "is not allowed"},
+	}
+	actualValue = err
+	if !reflect.DeepEqual(actualValue, expectedValue) {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v\nexpectedValue:%v\n", actualValue, expectedValue)
+	}
+
+	//
+	//
+	//---------------------
+	// success (validate a node)
+	roughYamlObj = FromYaml(`server: {host: x}`)
+	if err := schema.Validate(roughYamlObj.Get("server")); err == nil {
+		t.Errorf("<< FAILED >>>")
+	}
+}
+
+func TestSchemaCombinators(t *testing.T) {
+	//---------------------
+	// init
+	schema, err := LoadSchema(`{
+  "type": "object",
+  "properties": {
+    "port": {"oneOf": [{"type": "integer"}, {"type": "string", "pattern": "^[0-9]+$"}]},
+    "size": {"anyOf": [{"type": "null"}, {"multipleOf": 1024}]},
+    "name": {"not": {"const": "root"}},
+    "tls": {"type": "boolean"}
+  },
+  "if": {"properties": {"tls": {"const": true}}, "required": ["tls"]},
+  "then": {"required": ["cert"]},
+  "dependentRequired": {"cert": ["key"]}
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var expectedValue interface{}
+	var actualValue interface{}
+
+	//
+	//
+	//---------------------
+	// error (keywords)
+	roughYamlObj := FromYaml(`
+port: 80.5
+size: 1000
+name: root
+tls: true
+`)
+	var keywords []string
+	for _, validationError := range schema.Validate(&roughYamlObj).(ValidationErrors) {
+		keywords = append(keywords, validationError.Keyword)
+	}
+	expectedValue = []string{"required", "oneOf", "anyOf", "not"}
+	actualValue = keywords
+	if !reflect.DeepEqual(actualValue, expectedValue) {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+
+	//
+	//
+	//---------------------
+	// success
+	roughYamlObj = FromYaml(`
+port: "8080"
+size: 2048
+tls: true
+cert: a.pem
+key: a.key
+`)
+	if err := schema.Validate(&roughYamlObj); err != nil {
+		t.Errorf("<< FAILED >>> : %v", err)
+	}
+}
+
+func TestLoadSchemaFile(t *testing.T) {
+	//---------------------
+	// init
+	dir, err := ioutil.TempDir("", "goroughyaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(filepath.Join(dir, "schema.json"), []byte(`{"type": "string"}`), 0600)
+	ioutil.WriteFile(filepath.Join(dir, "invalid.yaml"), []byte(`{pattern: "["}`), 0600)
+	ioutil.WriteFile(filepath.Join(dir, "reference.yaml"), []byte(`{$ref: "#/$defs/missing"}`), 0600)
+
+	//
+	//
+	//---------------------
+	// success
+	if _, err := LoadSchemaFile(filepath.Join(dir, "schema.json")); err != nil {
+		t.Errorf("<< FAILED >>> : %v", err)
+	}
+
+	//
+	//
+	//---------------------
+	// error (invalid pattern and reference)
+	if _, err := LoadSchemaFile(filepath.Join(dir, "invalid.yaml")); err == nil {
+		t.Errorf("<< FAILED >>>")
+	}
+	if _, err := LoadSchemaFile(filepath.Join(dir, "reference.yaml")); err == nil {
+		t.Errorf("<< FAILED >>>")
+	}
+}
@@ -0,0 +1,79 @@
+package goroughyaml
+
+import (
+	"gopkg.in/yaml.v2"
+	"sort"
+)
+
+// SortKeys sorts the keys of the mapping with less, which reports whether key a goes before key b.
+// A nil less sorts keys by their string form. With recursive, mappings nested in the mapping are sorted too,
+// including mappings in sequences.
+func (o *RoughYaml) SortKeys(recursive bool, less func(a interface{}, b interface{}) bool) error {
+	mapSlice, err := o.mapping()
+	if err != nil {
+		return err
+	}
+	if less == nil {
+		less = func(a interface{}, b interface{}) bool {
+			return keyString(a) < keyString(b)
+		}
+	}
+	return o.recordEdit(recursive, Change{Operation: OperationReorder, Path: o.Path()}, nil, func() error {
+		sortMapSlice(*mapSlice, recursive, less)
+		return nil
+	})
+}
+
+func sortMapSlice(mapSlice yaml.MapSlice, recursive bool, less func(a interface{}, b interface{}) bool) {
+	sort.SliceStable(mapSlice, func(i, j int) bool {
+		return less(mapSlice[i].Key, mapSlice[j].Key)
+	})
+	if recursive {
+		for _, item := range mapSlice {
+			sortValue(item.Value, less)
+		}
+	}
+}
+
+func sortValue(value interface{}, less func(a interface{}, b interface{}) bool) {
+	switch v := value.(type) {
+	case yaml.MapSlice:
+		sortMapSlice(v, true, less)
+	case *yaml.MapSlice:
+		if v != nil {
+			sortMapSlice(*v, true, less)
+		}
+	case []interface{}:
+		for _, element := range v {
+			sortValue(element, less)
+		}
+	}
+}
+
+// ReorderBy moves the keys named in preferredOrder to the front of the mapping, in that order.
+// The other keys follow in their current relative order.
+func (o *RoughYaml) ReorderBy(preferredOrder []string) error {
+	mapSlice, err := o.mapping()
+	if err != nil {
+		return err
+	}
+	rank := make(map[string]int, len(preferredOrder))
+	for index, key := range preferredOrder {
+		if _, ok := rank[key]; !ok {
+			rank[key] = index
+		}
+	}
+	rankOf := func(key interface{}) int {
+		if index, ok := rank[keyString(key)]; ok {
+			return index
+		}
+		return len(preferredOrder)
+	}
+	items := *mapSlice
+	return o.recordEdit(false, Change{Operation: OperationReorder, Path: o.Path()}, nil, func() error {
+		sort.SliceStable(items, func(i, j int) bool {
+			return rankOf(items[i].Key) < rankOf(items[j].Key)
+		})
+		return nil
+	})
+}
@@ -0,0 +1,108 @@
+package goroughyaml
+
+import (
+	"testing"
+)
+
+func TestSortKeys(t *testing.T) {
+	//---------------------
+	// init
+	yamlString := `
+ccc:
+  zzz: 1
+  yyy: 2
+aaa:
+  - ddd: 1
+    bbb: 2
+bbb: 3
+`
+	var expectedValue interface{}
+	var actualValue interface{}
+
+	//
+	//
+	//---------------------
+	// success (not recursive)
+	roughYamlObj := FromYaml(yamlString)
+	roughYamlObj.SortKeys(false, nil)
+	expectedValue = `aaa:
+- ddd: 1
+  bbb: 2
+bbb: 3
+ccc:
+  zzz: 1
+  yyy: 2
+`
+	actualValue, _ = roughYamlObj.ToYaml()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+
+	//
+	//
+	//---------------------
+	// success (recursive)
+	roughYamlObj = FromYaml(yamlString)
+	roughYamlObj.SortKeys(true, func(a interface{}, b interface{}) bool {
+		return a.(string) < b.(string)
+	})
+	expectedValue = `aaa:
+- bbb: 2
+  ddd: 1
+bbb: 3
+ccc:
+  yyy: 2
+  zzz: 1
+`
+	actualValue, _ = roughYamlObj.ToYaml()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+
+	//
+	//
+	//---------------------
+	// error (not a mapping)
+	if roughYamlObj.Get("bbb").SortKeys(false, nil) == nil {
+		t.Errorf("<< FAILED >>>")
+	}
+}
+
+func TestReorderBy(t *testing.T) {
+	//---------------------
+	// init
+	yamlString := `
+spec:
+  replicas: 1
+metadata:
+  name: app
+status: {}
+kind: Deployment
+apiVersion: apps/v1
+`
+	var expectedValue interface{}
+	var actualValue interface{}
+
+	roughYamlObj := FromYaml(yamlString)
+
+	//
+	//
+	//---------------------
+	// success (unnamed keys keep their relative order)
+	roughYamlObj.ReorderBy([]string{"apiVersion", "kind", "metadata", "spec"})
+	expectedValue = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  replicas: 1
+status: {}
+`
+	actualValue, _ = roughYamlObj.ToYaml()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+}
@@ -0,0 +1,67 @@
+package goroughyaml
+
+import (
+	"gopkg.in/yaml.v2"
+	"io"
+)
+
+// Decoder reads yaml documents from an input stream.
+type Decoder struct {
+	decoder *yaml.Decoder
+}
+
+// NewDecoder returns a new decoder that reads from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{decoder: yaml.NewDecoder(r)}
+}
+
+// Decode reads the next yaml document from its input and stores it in v.
+// It returns io.EOF when there are no more documents.
+func (d *Decoder) Decode(v *RoughYaml) error {
+	mapSlice := &yaml.MapSlice{}
+	if err := d.decoder.Decode(mapSlice); err != nil {
+		return err
+	}
+	*v = newRoughYaml(mapSlice)
+	return nil
+}
+
+// Encoder writes yaml documents to an output stream.
+type Encoder struct {
+	w       io.Writer
+	options *YamlOptions
+	count   int
+}
+
+// NewEncoder returns a new encoder that writes to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// SetOptions makes the encoder lay out documents as ToYamlWithOptions does.
+func (e *Encoder) SetOptions(options YamlOptions) {
+	e.options = &options
+}
+
+// Encode writes v to the stream.
+// From the second document on, a "---" separator is written before the document.
+func (e *Encoder) Encode(v *RoughYaml) error {
+	var content string
+	var err error
+	if e.options != nil {
+		content, err = v.ToYamlWithOptions(*e.options)
+	} else {
+		content, err = v.ToYaml()
+	}
+	if err != nil {
+		return err
+	}
+	if e.count > 0 && (e.options == nil || !e.options.DocumentStart) {
+		content = "---\n" + content
+	}
+	if _, err := io.WriteString(e.w, content); err != nil {
+		return err
+	}
+	e.count++
+	return nil
+}
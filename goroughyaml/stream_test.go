@@ -0,0 +1,122 @@
+package goroughyaml
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestDecoder(t *testing.T) {
+	//---------------------
+	// init
+	yamlString := `
+aaa:
+  bbb: bbb1
+---
+aaa:
+  bbb: bbb2
+---
+aaa:
+  bbb: bbb3
+`
+	var expectedValue interface{}
+	var actualValue interface{}
+
+	decoder := NewDecoder(strings.NewReader(yamlString))
+
+	//
+	//
+	//---------------------
+	// success (read documents one at a time)
+	for _, expectedValue = range []string{"bbb1", "bbb2", "bbb3"} {
+		roughYamlObj := FromYaml("")
+		if err := decoder.Decode(&roughYamlObj); err != nil {
+			t.Errorf("<< FAILED >>> : %v", err)
+			return
+		}
+		actualValue = roughYamlObj.Get("aaa").Get("bbb").Value()
+		if actualValue != expectedValue {
+			t.Errorf("<< FAILED >>>")
+		}
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+
+	//
+	//
+	//---------------------
+	// success (end of stream)
+	roughYamlObj := FromYaml("")
+	if err := decoder.Decode(&roughYamlObj); err != io.EOF {
+		t.Errorf("<< FAILED >>> : %v", err)
+	}
+
+	//
+	//
+	//---------------------
+	// error (invalid yaml)
+	decoder = NewDecoder(strings.NewReader("aaa: [bbb"))
+	if err := decoder.Decode(&roughYamlObj); err == nil || err == io.EOF {
+		t.Errorf("<< FAILED >>> : %v", err)
+	}
+}
+
+func TestEncoder(t *testing.T) {
+	//---------------------
+	// init
+	roughYamlObj1 := FromYaml(`
+aaa:
+  bbb: bbb1
+`)
+	roughYamlObj2 := FromYaml(`
+aaa:
+  ccc:
+  - ccc1
+`)
+	var expectedValue interface{}
+	var actualValue interface{}
+
+	//
+	//
+	//---------------------
+	// success (documents are separated)
+	expectedValue = `aaa:
+  bbb: bbb1
+---
+aaa:
+  ccc:
+  - ccc1
+`
+	buffer := &bytes.Buffer{}
+	encoder := NewEncoder(buffer)
+	encoder.Encode(&roughYamlObj1)
+	encoder.Encode(&roughYamlObj2)
+	actualValue = buffer.String()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+
+	//
+	//
+	//---------------------
+	// success (with options)
+	expectedValue = `---
+aaa:
+  bbb: bbb1
+---
+aaa:
+  ccc:
+    - ccc1
+`
+	buffer = &bytes.Buffer{}
+	encoder = NewEncoder(buffer)
+	encoder.SetOptions(YamlOptions{ListIndent: ListIndentIndented, DocumentStart: true})
+	encoder.Encode(&roughYamlObj1)
+	encoder.Encode(&roughYamlObj2)
+	actualValue = buffer.String()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+}
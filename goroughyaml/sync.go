@@ -0,0 +1,101 @@
+package goroughyaml
+
+import (
+	"errors"
+	"sync"
+)
+
+// SyncRoughYaml is a document which can be read and written by multiple goroutines.
+// Reads return deep copies, so that what they return is not affected by later writes.
+type SyncRoughYaml struct {
+	mutex sync.RWMutex
+	root  *RoughYaml
+}
+
+// NewSyncRoughYaml returns a concurrency-safe document which takes over root.
+// root must not be used directly afterwards.
+func NewSyncRoughYaml(root *RoughYaml) *SyncRoughYaml {
+	return &SyncRoughYaml{root: root}
+}
+
+// Get returns a copy of the node at path.
+func (s *SyncRoughYaml) Get(path ...string) *RoughYaml {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.root.GetPath(path...).Clone()
+}
+
+// Value returns a copy of the value at path.
+func (s *SyncRoughYaml) Value(path ...string) interface{} {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return cloneValue(s.root.GetPath(path...).Value())
+}
+
+// Set replaces the value at path. It returns an error if the path does not exist.
+func (s *SyncRoughYaml) Set(path []string, value interface{}) error {
+	return s.update(path, func(parent *RoughYaml, key string) error {
+		return parent.Set(key, cloneValue(value))
+	})
+}
+
+// SetForce replaces the value at path, adding the last key of path if it does not exist.
+func (s *SyncRoughYaml) SetForce(path []string, value interface{}) error {
+	return s.update(path, func(parent *RoughYaml, key string) error {
+		return parent.SetForce(key, cloneValue(value))
+	})
+}
+
+// Delete removes the key at path.
+func (s *SyncRoughYaml) Delete(path ...string) error {
+	return s.update(path, func(parent *RoughYaml, key string) error {
+		return parent.Delete(key)
+	})
+}
+
+func (s *SyncRoughYaml) update(path []string, fn func(parent *RoughYaml, key string) error) error {
+	if len(path) == 0 {
+		return errors.New("path is empty")
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return fn(s.root.GetPath(path[:len(path)-1]...), path[len(path)-1])
+}
+
+// ToYaml returns the document as yaml.
+func (s *SyncRoughYaml) ToYaml() (string, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.root.ToYaml()
+}
+
+// Read calls fn with the root while holding the read lock.
+// fn must neither modify the document nor keep nodes of it after returning.
+func (s *SyncRoughYaml) Read(fn func(root *RoughYaml)) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	fn(s.root)
+}
+
+// Update calls fn with the root while holding the write lock, so that several edits are applied at once.
+// fn must not keep nodes of the document after returning.
+func (s *SyncRoughYaml) Update(fn func(root *RoughYaml) error) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return fn(s.root)
+}
+
+// Snapshot returns a copy of the whole document.
+func (s *SyncRoughYaml) Snapshot() *RoughYaml {
+	return s.Get()
+}
+
+// Swap replaces the whole document with root and returns the previous one.
+// root must not be used directly afterwards.
+func (s *SyncRoughYaml) Swap(root *RoughYaml) *RoughYaml {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	previous := s.root
+	s.root = root
+	return previous
+}
@@ -0,0 +1,121 @@
+package goroughyaml
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestSyncRoughYaml(t *testing.T) {
+	//---------------------
+	// init
+	yamlString := `
+aaa:
+  bbb: bbb1
+  ccc:
+  - 1
+  - 2
+`
+	var expectedValue interface{}
+	var actualValue interface{}
+
+	roughYamlObj := FromYaml(yamlString)
+	syncRoughYaml := NewSyncRoughYaml(&roughYamlObj)
+
+	//
+	//
+	//---------------------
+	// success (get and set)
+	syncRoughYaml.Set([]string{"aaa", "bbb"}, "bbb2")
+	syncRoughYaml.SetForce([]string{"aaa", "ddd"}, "ddd1")
+	expectedValue = "bbb2"
+	actualValue = syncRoughYaml.Value("aaa", "bbb")
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+	}
+	t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	expectedValue = 2
+	actualValue = syncRoughYaml.Get("aaa", "ccc").Get("1").Value()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+	}
+	t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+
+	//
+	//
+	//---------------------
+	// success (returned nodes are copies)
+	node := syncRoughYaml.Get("aaa")
+	node.Set("bbb", "changed")
+	syncRoughYaml.Delete("aaa", "ddd")
+	expectedValue = `aaa:
+  bbb: bbb2
+  ccc:
+  - 1
+  - 2
+`
+	actualValue, _ = syncRoughYaml.ToYaml()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+
+	//
+	//
+	//---------------------
+	// error (missing path)
+	if syncRoughYaml.Set([]string{"xxx", "yyy"}, 1) == nil || syncRoughYaml.Delete() == nil {
+		t.Errorf("<< FAILED >>>")
+	}
+}
+
+func TestSyncRoughYamlConcurrency(t *testing.T) {
+	//---------------------
+	// init
+	roughYamlObj := FromYaml(`
+aaa:
+  bbb: 0
+`)
+	syncRoughYaml := NewSyncRoughYaml(&roughYamlObj)
+
+	//
+	//
+	//---------------------
+	// success (readers and writers run at the same time, check with go test -race)
+	waitGroup := sync.WaitGroup{}
+	for i := 0; i < 4; i++ {
+		waitGroup.Add(2)
+		go func(i int) {
+			defer waitGroup.Done()
+			for j := 0; j < 100; j++ {
+				key := "key" + strconv.Itoa(i)
+				syncRoughYaml.SetForce([]string{"aaa", key}, j)
+				syncRoughYaml.Set([]string{"aaa", "bbb"}, j)
+				syncRoughYaml.Update(func(root *RoughYaml) error {
+					return root.Get("aaa").Delete(key)
+				})
+			}
+		}(i)
+		go func() {
+			defer waitGroup.Done()
+			for j := 0; j < 100; j++ {
+				syncRoughYaml.Value("aaa", "bbb")
+				syncRoughYaml.ToYaml()
+				node := syncRoughYaml.Get("aaa")
+				node.SetForce("ccc", j)
+				syncRoughYaml.Read(func(root *RoughYaml) {
+					root.Get("aaa").Get("bbb").Value()
+				})
+			}
+		}()
+	}
+	waitGroup.Wait()
+	expectedValue := `aaa:
+  bbb: 99
+`
+	actualValue, _ := syncRoughYaml.ToYaml()
+	if actualValue != expectedValue {
+		t.Errorf("<< FAILED >>>")
+		t.Logf("actualValue:%v, expectedValue:%v\n", actualValue, expectedValue)
+	}
+}
@@ -0,0 +1,150 @@
+package goroughyaml
+
+import (
+	"crypto/sha256"
+	"io/ioutil"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WatchOptions configures Watch.
+type WatchOptions struct {
+	// Interval is the time between checks of the file. 0 means one second.
+	Interval time.Duration
+	// Validate rejects a new document by returning an error. The last good document is kept.
+	Validate func(doc *RoughYaml) error
+	// OnReload is called after a new document has been swapped in, with the changes from the previous one.
+	OnReload func(doc *RoughYaml, changes []Change)
+	// OnError is called when the file cannot be read or parsed, or Validate rejects it.
+	// The same error for the same content of the file is reported once.
+	OnError func(err error)
+}
+
+// Watcher keeps the document of a yaml file up to date with the file.
+// The file is read at an interval and reloaded when its content changes.
+type Watcher struct {
+	path     string
+	options  WatchOptions
+	current  atomic.Value
+	mutex    sync.Mutex
+	checksum [sha256.Size]byte
+	failed   [sha256.Size]byte
+	err      error
+	stop     chan struct{}
+	done     chan struct{}
+	once     sync.Once
+}
+
+// Watch loads the yaml file at path and starts watching it for changes.
+// It returns an error if the file cannot be loaded or Validate rejects it.
+func Watch(path string, options WatchOptions) (*Watcher, error) {
+	if options.Interval <= 0 {
+		options.Interval = time.Second
+	}
+	bytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := loadBytes(path, bytes)
+	if err != nil {
+		return nil, err
+	}
+	if options.Validate != nil {
+		if err := options.Validate(&doc); err != nil {
+			return nil, err
+		}
+	}
+	w := &Watcher{
+		path:     path,
+		options:  options,
+		checksum: sha256.Sum256(bytes),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	w.current.Store(&doc)
+	go w.run()
+	return w, nil
+}
+
+// Current returns the last good document. It is safe to call from any goroutine.
+// The document is shared by all callers and must not be changed; use Clone to edit it.
+func (w *Watcher) Current() *RoughYaml {
+	return w.current.Load().(*RoughYaml)
+}
+
+// Err returns the error of the last reload, or nil if the current content of the file has been loaded.
+func (w *Watcher) Err() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.err
+}
+
+// Reload checks the file now, without waiting for the interval, and returns the error of the reload.
+// OnReload and OnError are called before it returns, so they must not call Reload or Close.
+func (w *Watcher) Reload() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	bytes, err := ioutil.ReadFile(w.path)
+	if err != nil {
+		return w.fail(err, [sha256.Size]byte{})
+	}
+	checksum := sha256.Sum256(bytes)
+	if checksum == w.checksum {
+		w.err = nil
+		return nil
+	}
+	if w.err != nil && checksum == w.failed {
+		return w.err
+	}
+	doc, err := loadBytes(w.path, bytes)
+	if err != nil {
+		return w.fail(err, checksum)
+	}
+	if w.options.Validate != nil {
+		if err := w.options.Validate(&doc); err != nil {
+			return w.fail(err, checksum)
+		}
+	}
+	previous := w.Current()
+	w.current.Store(&doc)
+	w.checksum = checksum
+	w.err = nil
+	if w.options.OnReload != nil {
+		w.options.OnReload(&doc, Diff(previous, &doc))
+	}
+	return nil
+}
+
+func (w *Watcher) fail(err error, checksum [sha256.Size]byte) error {
+	repeated := w.err != nil && w.failed == checksum && w.err.Error() == err.Error()
+	w.err = err
+	w.failed = checksum
+	if w.options.OnError != nil && !repeated {
+		w.options.OnError(err)
+	}
+	return err
+}
+
+// Close stops watching the file. Current keeps returning the last good document.
+func (w *Watcher) Close() error {
+	w.once.Do(func() {
+		close(w.stop)
+	})
+	<-w.done
+	return nil
+}
+
+func (w *Watcher) run() {
+	defer close(w.done)
+	ticker := time.NewTicker(w.options.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			w.Reload()
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package goroughyaml
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatch(t *testing.T) {
+	//---------------------
+	// init
+	dir, err := ioutil.TempDir("", "goroughyaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.yaml")
+	ioutil.WriteFile(path, []byte("aaa: 1\n"), 0600)
+
+	var expectedValue interface{}
+	var actualValue interface{}
+
+	var reloads [][]Change
+	var errs []error
+	watcher, err := Watch(path, WatchOptions{
+		Interval: time.Hour,
+		Validate: func(doc *RoughYaml) error {
+			if doc.Get("aaa").Value() == nil {
+				return errors.New("aaa is required")
+			}
+			return nil
+		},
+		OnReload: func(doc *RoughYaml, changes []Change) {
+			reloads = append(reloads, changes)
+		},
+		OnError: func(err error) {
+			errs = append(errs, err)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer watcher.Close()
+
+	//
+	//
+	//---------------------
+	// success (reload swaps the document and reports the changes)
+	ioutil.WriteFile(path, []byte("aaa: 2\n"), 0600)
+	if err := watcher.Reload(); err != nil {
+		t.Errorf("<< FAILED >>> : %v", err)
+	}
+	expectedValue = 2
+	actualValue = watcher.Current().Get("aaa").Value()
+	if actualValue != expectedValue || len(reloads) != 1 || len(reloads[0]) != 1 || reloads[0][0].NewValue != 2 {
+		t.Errorf("<< FAILED >>>")
+	}
+	t.Logf("actualValue:%v, expectedValue:%v, reloads:%v\n", actualValue, expectedValue, reloads)
+
+	//
+	//
+	//---------------------
+	// success (unchanged content is not reloaded)
+	watcher.Reload()
+	if len(reloads) != 1 {
+		t.Errorf("<< FAILED >>>")
+	}
+
+	//
+	//
+	//---------------------
+	// error (invalid yaml and failed validation keep the last good document)
+	ioutil.WriteFile(path, []byte("aaa: [2\n"), 0600)
+	if watcher.Reload() == nil || watcher.Reload() == nil || watcher.Err() == nil {
+		t.Errorf("<< FAILED >>>")
+	}
+	ioutil.WriteFile(path, []byte("bbb: 2\n"), 0600)
+	if watcher.Reload() == nil {
+		t.Errorf("<< FAILED >>>")
+	}
+	expectedValue = 2
+	actualValue = watcher.Current().Get("aaa").Value()
+	if actualValue != expectedValue || len(errs) != 2 {
+		t.Errorf("<< FAILED >>>")
+	}
+	t.Logf("actualValue:%v, expectedValue:%v, errs:%v\n", actualValue, expectedValue, errs)
+}
+
+func TestWatchPolling(t *testing.T) {
+	//---------------------
+	// init
+	dir, err := ioutil.TempDir("", "goroughyaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.yaml")
+	ioutil.WriteFile(path, []byte("aaa: 1\n"), 0600)
+
+	reloaded := make(chan *RoughYaml, 1)
+	watcher, err := Watch(path, WatchOptions{
+		Interval: 10 * time.Millisecond,
+		OnReload: func(doc *RoughYaml, changes []Change) {
+			reloaded <- doc
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer watcher.Close()
+
+	//
+	//
+	//---------------------
+	// success (the file is reloaded in the background)
+	ioutil.WriteFile(path, []byte("aaa: 2\n"), 0600)
+	select {
+	case doc := <-reloaded:
+		if doc.Get("aaa").Value() != 2 || watcher.Current() != doc {
+			t.Errorf("<< FAILED >>>")
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("<< FAILED >>> : not reloaded")
+	}
+}